@@ -0,0 +1,215 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Func executes a single request, and returns the time the server reports it spent on it
+type Func func(ctx context.Context) (time.Duration, error)
+
+type Sample struct {
+	Latency    time.Duration
+	ServerTime time.Duration
+	Err        error
+}
+
+type Config struct {
+	Concurrency int
+	Duration    time.Duration
+	// Requests stops the benchmark after this many requests, if it is larger than zero
+	Requests int
+}
+
+type Result struct {
+	Samples []Sample
+	Elapsed time.Duration
+}
+
+// Run executes fn from Config.Concurrency goroutines until Config.Duration has passed, Config.Requests have been
+// made, or the context is cancelled.
+func Run(ctx context.Context, cfg Config, fn Func) Result {
+	ctx, cancel := context.WithTimeout(ctx, cfg.Duration)
+	defer cancel()
+
+	var mu sync.Mutex
+	var samples []Sample
+	var started int
+
+	// next reserves a request slot, and returns false when the benchmark is done
+	next := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		if ctx.Err() != nil || (cfg.Requests > 0 && started >= cfg.Requests) {
+			return false
+		}
+		started++
+		return true
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < max(cfg.Concurrency, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for next() {
+				t := time.Now()
+				server, err := fn(ctx)
+				s := Sample{Latency: time.Since(t), ServerTime: server, Err: err}
+
+				// requests interrupted by the end of the benchmark aren't counted
+				if err != nil && ctx.Err() != nil {
+					return
+				}
+
+				mu.Lock()
+				samples = append(samples, s)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return Result{Samples: samples, Elapsed: time.Since(start)}
+}
+
+// Errors returns the number of failed requests
+func (r Result) Errors() int {
+	var n int
+	for _, s := range r.Samples {
+		if s.Err != nil {
+			n++
+		}
+	}
+
+	return n
+}
+
+// ErrorRate returns the fraction of requests which failed
+func (r Result) ErrorRate() float64 {
+	if len(r.Samples) == 0 {
+		return 0
+	}
+
+	return float64(r.Errors()) / float64(len(r.Samples))
+}
+
+// Throughput returns the number of requests per second
+func (r Result) Throughput() float64 {
+	if r.Elapsed == 0 {
+		return 0
+	}
+
+	return float64(len(r.Samples)) / r.Elapsed.Seconds()
+}
+
+// Latencies returns the sorted client round trip times of the successful requests
+func (r Result) Latencies() Durations {
+	return r.durations(func(s Sample) time.Duration { return s.Latency })
+}
+
+// ServerTimes returns the sorted server side times of the successful requests
+func (r Result) ServerTimes() Durations {
+	return r.durations(func(s Sample) time.Duration { return s.ServerTime })
+}
+
+func (r Result) durations(fn func(Sample) time.Duration) Durations {
+	var d Durations
+	for _, s := range r.Samples {
+		if s.Err == nil {
+			d = append(d, fn(s))
+		}
+	}
+	sort.Slice(d, func(i, j int) bool { return d[i] < d[j] })
+
+	return d
+}
+
+// Durations is a sorted list of durations
+type Durations []time.Duration
+
+// Percentile returns the p-th percentile (0-100) using the nearest rank method
+func (d Durations) Percentile(p float64) time.Duration {
+	if len(d) == 0 {
+		return 0
+	}
+
+	rank := int(p/100*float64(len(d))+0.5) - 1
+	rank = min(max(rank, 0), len(d)-1)
+
+	return d[rank]
+}
+
+func (d Durations) Max() time.Duration {
+	if len(d) == 0 {
+		return 0
+	}
+
+	return d[len(d)-1]
+}
+
+func (d Durations) Mean() time.Duration {
+	if len(d) == 0 {
+		return 0
+	}
+
+	var total time.Duration
+	for _, x := range d {
+		total += x
+	}
+
+	return total / time.Duration(len(d))
+}
+
+// Histogram writes a histogram of the durations with the given number of equally sized buckets,
+// with bars scaled to at most width characters.
+func (d Durations) Histogram(out io.Writer, buckets, width int) {
+	if len(d) == 0 || buckets < 1 {
+		return
+	}
+
+	low, high := d[0], d[len(d)-1]
+	size := (high - low) / time.Duration(buckets)
+	if size == 0 {
+		size = 1
+		buckets = 1
+	}
+
+	counts := make([]int, buckets)
+	for _, x := range d {
+		i := min(int((x-low)/size), buckets-1)
+		counts[i]++
+	}
+
+	var highest int
+	for _, c := range counts {
+		highest = max(highest, c)
+	}
+
+	for i, c := range counts {
+		from := low + time.Duration(i)*size
+		to := from + size
+		if i == buckets-1 {
+			to = high
+		}
+		bar := strings.Repeat("█", c*width/highest)
+		_, _ = fmt.Fprintf(out, "%10s - %-10s | %-*s %d\n", round(from), round(to), width, bar, c)
+	}
+}
+
+func round(d time.Duration) time.Duration {
+	switch {
+	case d > time.Second:
+		return d.Round(time.Millisecond)
+	case d > time.Millisecond:
+		return d.Round(10 * time.Microsecond)
+	default:
+		return d.Round(time.Microsecond)
+	}
+}
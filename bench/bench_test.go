@@ -0,0 +1,57 @@
+package bench_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rockset/cli/bench"
+)
+
+func TestRun(t *testing.T) {
+	var calls atomic.Int32
+	result := bench.Run(context.Background(), bench.Config{Concurrency: 4, Duration: time.Minute, Requests: 20},
+		func(ctx context.Context) (time.Duration, error) {
+			if calls.Add(1)%5 == 0 {
+				return 0, errors.New("boom")
+			}
+			return time.Millisecond, nil
+		})
+
+	assert.Len(t, result.Samples, 20)
+	assert.Equal(t, 4, result.Errors())
+	assert.InDelta(t, 0.2, result.ErrorRate(), 0.001)
+	assert.Len(t, result.ServerTimes(), 16)
+	assert.Equal(t, time.Millisecond, result.ServerTimes().Max())
+}
+
+func TestDurations_Percentile(t *testing.T) {
+	var d bench.Durations
+	for i := 1; i <= 100; i++ {
+		d = append(d, time.Duration(i)*time.Millisecond)
+	}
+
+	assert.Equal(t, 50*time.Millisecond, d.Percentile(50))
+	assert.Equal(t, 90*time.Millisecond, d.Percentile(90))
+	assert.Equal(t, 99*time.Millisecond, d.Percentile(99))
+	assert.Equal(t, 100*time.Millisecond, d.Max())
+	assert.Equal(t, time.Duration(0), bench.Durations{}.Percentile(50))
+}
+
+func TestDurations_Histogram(t *testing.T) {
+	d := bench.Durations{time.Millisecond, time.Millisecond, 2 * time.Millisecond, 5 * time.Millisecond}
+
+	var out bytes.Buffer
+	d.Histogram(&out, 4, 10)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 4)
+	assert.True(t, strings.HasSuffix(lines[0], "██████████ 2"), lines[0])
+	assert.True(t, strings.HasSuffix(lines[3], "█████      1"), lines[3])
+}
@@ -0,0 +1,96 @@
+// Package cascade deletes groups of resources concurrently, and keeps going when some of them fail
+package cascade
+
+import (
+	"context"
+	"sync"
+)
+
+// Step deletes a single resource
+type Step struct {
+	// Resource describes the resource in the output, e.g. "collection commons.movies"
+	Resource string
+	// Delete starts deleting the resource
+	Delete func(ctx context.Context) error
+	// Gone waits until the resource is gone, and is optional
+	Gone func(ctx context.Context) error
+}
+
+// Result is the outcome of a Step
+type Result struct {
+	Resource string
+	Err      error
+}
+
+// ProgressFn is called after each step, with how many steps are done out of the total
+type ProgressFn func(done, total int, r Result)
+
+// Run runs the steps of each phase using at most concurrency goroutines, and the next phase isn't started until all
+// steps of the current phase are done, so resources which depend on others can be deleted first. A failed step
+// doesn't stop the other steps, and the results are returned in the order the steps completed.
+func Run(ctx context.Context, concurrency int, phases [][]Step, progress ProgressFn) []Result {
+	var total int
+	for _, steps := range phases {
+		total += len(steps)
+	}
+
+	var mu sync.Mutex
+	var results []Result
+
+	for _, steps := range phases {
+		queue := make(chan Step)
+		var wg sync.WaitGroup
+		for i := 0; i < min(max(concurrency, 1), len(steps)); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for s := range queue {
+					r := Result{Resource: s.Resource, Err: run(ctx, s)}
+
+					mu.Lock()
+					results = append(results, r)
+					if progress != nil {
+						progress(len(results), total, r)
+					}
+					mu.Unlock()
+				}
+			}()
+		}
+
+		for _, s := range steps {
+			queue <- s
+		}
+		close(queue)
+		wg.Wait()
+	}
+
+	return results
+}
+
+func run(ctx context.Context, s Step) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := s.Delete(ctx); err != nil {
+		return err
+	}
+
+	if s.Gone == nil {
+		return nil
+	}
+
+	return s.Gone(ctx)
+}
+
+// Failed returns the results of the steps which failed
+func Failed(results []Result) []Result {
+	var failed []Result
+	for _, r := range results {
+		if r.Err != nil {
+			failed = append(failed, r)
+		}
+	}
+
+	return failed
+}
@@ -0,0 +1,93 @@
+package cascade_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/cli/cascade"
+)
+
+func TestRun(t *testing.T) {
+	var running, peak, collectionsStarted atomic.Int32
+	var dependentsDone atomic.Int32
+
+	step := func(name string, fail bool, fn func()) cascade.Step {
+		return cascade.Step{
+			Resource: name,
+			Delete: func(ctx context.Context) error {
+				n := running.Add(1)
+				defer running.Add(-1)
+				for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+				}
+				fn()
+				if fail {
+					return errors.New("boom")
+				}
+				return nil
+			},
+		}
+	}
+
+	var dependents, collections []cascade.Step
+	for i := 0; i < 10; i++ {
+		dependents = append(dependents, step(fmt.Sprintf("view %d", i), i == 3, func() { dependentsDone.Add(1) }))
+	}
+	for i := 0; i < 5; i++ {
+		collections = append(collections, step(fmt.Sprintf("collection %d", i), false, func() {
+			// all dependents must be done before the collections are deleted
+			assert.Equal(t, int32(10), dependentsDone.Load())
+			collectionsStarted.Add(1)
+		}))
+	}
+
+	var calls int
+	results := cascade.Run(context.Background(), 3, [][]cascade.Step{dependents, collections},
+		func(done, total int, r cascade.Result) {
+			calls++
+			assert.Equal(t, calls, done)
+			assert.Equal(t, 15, total)
+		})
+
+	assert.Len(t, results, 15)
+	assert.Equal(t, 15, calls)
+	assert.Equal(t, int32(5), collectionsStarted.Load())
+	assert.LessOrEqual(t, peak.Load(), int32(3))
+
+	failed := cascade.Failed(results)
+	require.Len(t, failed, 1)
+	assert.Equal(t, "view 3", failed[0].Resource)
+}
+
+func TestRunGone(t *testing.T) {
+	var gone bool
+	results := cascade.Run(context.Background(), 1, [][]cascade.Step{{{
+		Resource: "alias commons.movies",
+		Delete:   func(ctx context.Context) error { return nil },
+		Gone: func(ctx context.Context) error {
+			gone = true
+			return nil
+		},
+	}}}, nil)
+
+	assert.True(t, gone)
+	assert.Empty(t, cascade.Failed(results))
+}
+
+func TestRunCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := cascade.Run(ctx, 2, [][]cascade.Step{{{
+		Resource: "collection commons.movies",
+		Delete:   func(ctx context.Context) error { return nil },
+	}}}, nil)
+
+	require.Len(t, results, 1)
+	assert.ErrorIs(t, results[0].Err, context.Canceled)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/rockset/cli/config"
 	"github.com/rockset/cli/flag"
 	"github.com/rockset/cli/format"
+	"github.com/rockset/cli/graph"
 	"github.com/rockset/cli/sort"
 )
 
@@ -196,6 +197,12 @@ func NewDeleteAliasCmd() *cobra.Command {
 				return err
 			}
 
+			warnBrokenDependents(ctx, cmd, rs, graph.Node{Kind: graph.Alias, Workspace: ws, Name: name})
+
+			if ok, err := confirmDelete(cmd, []string{fmt.Sprintf("alias %s.%s", ws, name)}); err != nil || !ok {
+				return err
+			}
+
 			err = rs.DeleteAlias(ctx, ws, name)
 			if err != nil {
 				return err
@@ -207,6 +214,7 @@ func NewDeleteAliasCmd() *cobra.Command {
 	}
 	cmd.Flags().StringP(flag.Workspace, flag.WorkspaceShort, flag.DefaultWorkspace, "delete alias for the selected workspace")
 	_ = cmd.RegisterFlagCompletionFunc(flag.Workspace, completion.Workspace(Version))
+	addDeleteFlags(&cmd)
 
 	return &cmd
 }
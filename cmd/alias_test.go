@@ -54,7 +54,7 @@ func (s *AliasTestSuite) Test_3_Update() {
 
 func (s *AliasTestSuite) Test_4_Delete() {
 	c := cmd.NewRootCmd("test")
-	out := test.WrapAndExecute(s.T(), c, "delete", "alias", s.name)
+	out := test.WrapAndExecute(s.T(), c, "delete", "alias", s.name, "--yes")
 
 	s.Equal(fmt.Sprintf("alias %s deleted\n", s.name), out.String())
 }
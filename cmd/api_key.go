@@ -108,8 +108,14 @@ func NewDeleteAPIKeyCmd() *cobra.Command {
 			}
 
 			var options []option.APIKeyOption
+			item := fmt.Sprintf("apikey %s", args[0])
 			if email, _ := cmd.Flags().GetString(flag.Email); email != "" {
 				options = append(options, option.ForUser(email))
+				item += " of " + email
+			}
+
+			if ok, err := confirmDelete(cmd, []string{item}); err != nil || !ok {
+				return err
 			}
 
 			err = rs.DeleteAPIKey(ctx, args[0], options...)
@@ -125,6 +131,7 @@ func NewDeleteAPIKeyCmd() *cobra.Command {
 
 	cmd.Flags().String(flag.Email, "", "the email address of the user who's key to delete, defaults to self")
 	_ = cmd.RegisterFlagCompletionFunc(flag.Email, completion.Alias(Version))
+	addDeleteFlags(&cmd)
 
 	return &cmd
 }
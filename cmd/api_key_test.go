@@ -32,7 +32,7 @@ func TestAPIKeySuite(t *testing.T) {
 func (s *APIKeySuite) TearDownSuite() {
 	// try to remove the apikey in case it is lingering
 	c := cmd.NewRootCmd("test")
-	_ = test.Wrapper(s.T(), c, "delete", "apikey", s.name)
+	_ = test.Wrapper(s.T(), c, "delete", "apikey", s.name, "--yes")
 	_ = c.Execute()
 }
 
@@ -66,7 +66,7 @@ func (s *APIKeySuite) Test_3_Update() {
 
 func (s *APIKeySuite) Test_8_Delete() {
 	c := cmd.NewRootCmd("test")
-	out := test.WrapAndExecute(s.T(), c, "delete", "apikey", s.name)
+	out := test.WrapAndExecute(s.T(), c, "delete", "apikey", s.name, "--yes")
 
 	s.Equal(fmt.Sprintf("apikey %s deleted\n", s.name), out.String())
 }
@@ -2,10 +2,12 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -13,10 +15,15 @@ import (
 	"github.com/dustin/go-humanize"
 	"github.com/pkg/browser"
 	devauth "github.com/rockset/device-authorization"
+	"github.com/rockset/rockset-go-client"
+	"github.com/rockset/rockset-go-client/openapi"
 	"github.com/rockset/rockset-go-client/option"
 	"github.com/spf13/cobra"
 
+	"github.com/rockset/cli/completion"
 	"github.com/rockset/cli/config"
+	"github.com/rockset/cli/flag"
+	"github.com/rockset/cli/role"
 	"github.com/rockset/cli/tui"
 )
 
@@ -206,6 +213,245 @@ func newAuthKeyCmd() *cobra.Command {
 	return &cmd
 }
 
+// ErrNotAllowed is returned by can-i when the action isn't allowed, so it exits with a non-zero exit code
+var ErrNotAllowed = errors.New("not allowed")
+
+func newAuthCanICmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "can-i ACTION [RESOURCE]",
+		Args:  cobra.RangeArgs(1, 2),
+		Short: "check if an action is allowed",
+		Long: `check if an action is allowed, and which role allows it, for the current user, another user or an apikey.
+The resource is a workspace, virtual instance or integration, and isn't used for global actions.
+An apikey without a role has the roles of the user who owns it.
+
+Without --privilege-cluster the action is allowed if it is allowed in at least one cluster, and the clusters
+it is limited to are shown. The command exits with a non-zero exit code if the action isn't allowed.`,
+		Example: `	## check if you can query the commons workspace
+	rockset auth can-i QUERY_DATA_WS commons
+
+	## check if another user can create collections in the commons workspace
+	rockset auth can-i CREATE_COLLECTION_WS commons --user alice@example.com
+
+	## check if an apikey can suspend a virtual instance in a cluster, and use the exit code in a script
+	rockset auth can-i SUSPEND_RESUME_VI main --apikey ci --privilege-cluster usw2a1 && echo allowed`,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return role.Actions(), cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			p := role.Privilege{Action: args[0]}
+			if len(args) > 1 {
+				p.Resource = args[1]
+			}
+			p.Cluster, _ = cmd.Flags().GetString(flag.PrivilegeCluster)
+			if err := p.Validate(); err != nil {
+				return err
+			}
+
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			email, _ := cmd.Flags().GetString(flag.User)
+			apikey, _ := cmd.Flags().GetString(flag.APIKey)
+			who, roles, err := identityRoles(ctx, rs, email, apikey)
+			if err != nil {
+				return err
+			}
+
+			var definitions []role.Definition
+			for _, name := range roles {
+				r, err := rs.GetRole(ctx, name)
+				if err != nil {
+					return fmt.Errorf("failed to get role %s: %w", name, err)
+				}
+				definitions = append(definitions, role.FromAPI(r))
+			}
+
+			grants := role.Grants(definitions, p.Action, p.Resource, p.Cluster)
+			if len(grants) == 0 {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "no, %s can't %s, none of the roles %s allow it\n",
+					who, p, strings.Join(roles, ", "))
+				return ErrNotAllowed
+			}
+
+			// without a cluster, the action might only be allowed in some clusters
+			var clusters []string
+			for _, g := range grants {
+				cluster := g.Privilege.OnlyIn()
+				if cluster == "" || p.Cluster != "" {
+					clusters = nil
+					break
+				}
+				if !slices.Contains(clusters, cluster) {
+					clusters = append(clusters, cluster)
+				}
+			}
+
+			if len(clusters) > 0 {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "yes, %s can %s, but only in %s\n", who, p,
+					strings.Join(clusters, ", "))
+			} else {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "yes, %s can %s\n", who, p)
+			}
+			for _, g := range grants {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  allowed by role %s: %s\n", g.Role, g.Privilege)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flag.User, "", "email address of the user to check, defaults to self")
+	cmd.Flags().String(flag.APIKey, "", "name of the apikey to check, owned by --user or self")
+	cmd.Flags().String(flag.PrivilegeCluster, "",
+		"cluster the workspace or virtual instance action is done in, defaults to any cluster")
+	_ = cmd.RegisterFlagCompletionFunc(flag.User, completion.Email(Version))
+	_ = cmd.RegisterFlagCompletionFunc(flag.APIKey, completion.APIKey(Version))
+
+	return &cmd
+}
+
+// identityRoles returns who is checked and their roles. An apikey has its own role if it was created with one,
+// otherwise it has the roles of the user who owns it.
+func identityRoles(ctx context.Context, rs *rockset.RockClient, email, apikey string) (string, []string, error) {
+	who := "you"
+	if email != "" {
+		who = email
+	}
+
+	if apikey != "" {
+		var options []option.APIKeyOption
+		if email != "" {
+			options = append(options, option.ForUser(email))
+		}
+
+		key, err := rs.GetAPIKey(ctx, apikey, options...)
+		if err != nil {
+			return "", nil, err
+		}
+
+		who = "apikey " + apikey
+		if r := key.GetRole(); r != "" {
+			return who, []string{r}, nil
+		}
+	}
+
+	var user openapi.User
+	var err error
+	if email != "" {
+		user, err = rs.GetUser(ctx, email)
+	} else {
+		user, err = rs.GetCurrentUser(ctx)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	return who, user.Roles, nil
+}
+
+func newWhoAmICmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "whoami",
+		Args:  cobra.NoArgs,
+		Short: "show the current identity",
+		Long: `show the authentication context, organization, user, roles and when the token or apikey expires.
+An apikey with a role only has that role, otherwise it has all the roles of the user.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			name, err := config.ContextName(cmd)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			org, err := rs.GetOrganization(ctx)
+			if err != nil {
+				return err
+			}
+
+			user, err := rs.GetCurrentUser(ctx)
+			if err != nil {
+				return err
+			}
+
+			kind, roles, expires := "token", user.Roles, "unknown"
+			if token, found := cfg.Tokens[name]; found {
+				expires = formatExpiry(token.Expiration)
+			} else if secret, found := cfg.Keys[name]; found {
+				key, err := currentAPIKey(ctx, rs, secret.Key)
+				if err != nil {
+					return err
+				}
+
+				kind = "apikey " + key.Name
+				if r := key.GetRole(); r != "" {
+					roles = []string{r}
+				}
+				if expiry := key.GetExpiryTime(); expiry == "" {
+					expires = "never"
+				} else if t, err := time.Parse(time.RFC3339, expiry); err == nil {
+					expires = formatExpiry(t)
+				} else {
+					expires = expiry
+				}
+			}
+
+			out := cmd.OutOrStdout()
+			_, _ = fmt.Fprintf(out, "context:      %s (%s)\n", name, kind)
+			_, _ = fmt.Fprintf(out, "server:       %s\n", rs.APIServer)
+			_, _ = fmt.Fprintf(out, "organization: %s (%s)\n", org.GetDisplayName(), org.GetId())
+			_, _ = fmt.Fprintf(out, "user:         %s\n", user.Email)
+			_, _ = fmt.Fprintf(out, "roles:        %s\n", strings.Join(roles, ", "))
+			_, _ = fmt.Fprintf(out, "expires:      %s\n", expires)
+
+			return nil
+		},
+	}
+
+	return &cmd
+}
+
+// currentAPIKey returns the apikey of the current user with the secret, which is found by its identifier
+// as the configuration doesn't have the name of the apikey
+func currentAPIKey(ctx context.Context, rs *rockset.RockClient, secret string) (openapi.ApiKey, error) {
+	keys, err := rs.ListAPIKeys(ctx)
+	if err != nil {
+		return openapi.ApiKey{}, err
+	}
+
+	for _, k := range keys {
+		// the listed key is only an identifier, which is a part of the secret
+		if k.Key != "" && (strings.HasPrefix(secret, k.Key) || strings.HasSuffix(secret, k.Key)) {
+			return rs.GetAPIKey(ctx, k.Name)
+		}
+	}
+
+	return openapi.ApiKey{}, errors.New("could not find the apikey of the current context")
+}
+
+// formatExpiry returns how long until the time, and the time itself
+func formatExpiry(t time.Time) string {
+	return fmt.Sprintf("%s (%s)", humanize.Time(t), t.Format(time.RFC3339))
+}
+
 const (
 	DefaultAuthProvider = "auth0"
 	DefaultAuthServer   = "auth.rockset.com"
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/rockset/rockset-go-client/openapi"
+	"github.com/spf13/cobra"
+
+	"github.com/rockset/cli/bench"
+	"github.com/rockset/cli/completion"
+	"github.com/rockset/cli/config"
+	"github.com/rockset/cli/flag"
+	"github.com/rockset/cli/lookup"
+	"github.com/rockset/cli/tui"
+)
+
+func newBenchQueryCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "query [SQL]",
+		Short: "benchmark a query or query lambda",
+		Long: `execute a query or query lambda repeatedly, and report latency percentiles, throughput and errors.
+
+The client latency is the round trip time including the network, and the server latency is the
+elapsed time reported by Rockset.`,
+		Example: `	## run a query on a virtual instance from 8 concurrent clients for one minute
+	rockset bench query --vi analytics --concurrency 8 --duration 60s "SELECT COUNT(*) FROM movies"
+
+	## benchmark the latest version of a query lambda
+	rockset bench query --lambda mylambda --tag latest --param year:2000`,
+		Args:        cobra.RangeArgs(0, 1),
+		Annotations: group("query"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			concurrency, _ := cmd.Flags().GetInt(flag.Concurrency)
+			duration, _ := cmd.Flags().GetDuration(flag.Duration)
+			requests, _ := cmd.Flags().GetInt(flag.Requests)
+			lambda, _ := cmd.Flags().GetString(flag.Lambda)
+			file, _ := cmd.Flags().GetString(flag.File)
+			vi, _ := cmd.Flags().GetString(flag.VI)
+
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			var fn bench.Func
+			var target string
+			if lambda != "" {
+				if len(args) > 0 || file != "" {
+					return fmt.Errorf("--%s can't be combined with SQL", flag.Lambda)
+				}
+				if vi != "" {
+					return fmt.Errorf("query lambdas can't be executed on a specific virtual instance")
+				}
+
+				ws, _ := cmd.Flags().GetString(flag.Workspace)
+				opts, err := queryLambdaOptions(cmd)
+				if err != nil {
+					return err
+				}
+
+				target = fmt.Sprintf("query lambda %s.%s", ws, lambda)
+				fn = func(ctx context.Context) (time.Duration, error) {
+					resp, err := rs.ExecuteQueryLambda(ctx, ws, lambda, opts...)
+					return queryServerTime(resp, err)
+				}
+			} else {
+				sql, err := sqlFromArgsOrFile(args, file)
+				if err != nil {
+					return err
+				}
+
+				if vi == "" {
+					target = "query"
+					fn = func(ctx context.Context) (time.Duration, error) {
+						return queryServerTime(rs.Query(ctx, sql))
+					}
+				} else {
+					id, err := lookup.VirtualInstanceNameOrIDtoID(ctx, rs, vi)
+					if err != nil {
+						return err
+					}
+
+					target = fmt.Sprintf("query on virtual instance %s", vi)
+					fn = func(ctx context.Context) (time.Duration, error) {
+						return queryServerTime(rs.ExecuteQueryOnVirtualInstance(ctx, id, sql))
+					}
+				}
+			}
+
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "benchmarking %s for %s with concurrency %d...\n",
+				target, duration, concurrency)
+
+			result := bench.Run(ctx, bench.Config{
+				Concurrency: concurrency,
+				Duration:    duration,
+				Requests:    requests,
+			}, fn)
+
+			showBenchResult(cmd.OutOrStdout(), result)
+
+			if len(result.Samples) > 0 && result.Errors() == len(result.Samples) {
+				return fmt.Errorf("all %d requests failed: %w", len(result.Samples), result.Samples[0].Err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Int(flag.Concurrency, 1, "number of concurrent clients")
+	cmd.Flags().Duration(flag.Duration, 10*time.Second, "how long to run the benchmark")
+	cmd.Flags().Int(flag.Requests, 0, "stop after this many requests, 0 means no limit")
+
+	cmd.Flags().String(flag.File, "", "read SQL from file")
+	_ = cobra.MarkFlagFilename(cmd.Flags(), flag.File, ".sql")
+	cmd.Flags().String(flag.VI, "", "execute query on virtual instance")
+	_ = cmd.RegisterFlagCompletionFunc(flag.VI, completion.VirtualInstance(Version))
+
+	cmd.Flags().String(flag.Lambda, "", "benchmark this query lambda instead of SQL")
+	_ = cmd.RegisterFlagCompletionFunc(flag.Lambda, completion.Lambda(Version))
+	cmd.Flags().StringP(flag.Workspace, flag.WorkspaceShort, flag.DefaultWorkspace, "workspace of the query lambda")
+	_ = cmd.RegisterFlagCompletionFunc(flag.Workspace, completion.Workspace(Version))
+	cmd.Flags().String(flag.Version, "", "query lambda version")
+	cmd.Flags().String(flag.Tag, "", "query lambda tag")
+	cmd.Flags().StringArrayP(flag.Param, "p", nil, "query lambda parameters, as NAME:VALUE")
+	cmd.MarkFlagsMutuallyExclusive(flag.Version, flag.Tag)
+
+	return &cmd
+}
+
+// queryServerTime returns the elapsed time reported by the server, or an error if the query failed
+func queryServerTime(resp openapi.QueryResponse, err error) (time.Duration, error) {
+	if err != nil {
+		return 0, err
+	}
+	if resp.GetStatus() == "ERROR" {
+		return 0, queryResponseError(resp)
+	}
+
+	stats := resp.GetStats()
+	return time.Duration(stats.GetElapsedTimeMs()) * time.Millisecond, nil
+}
+
+func showBenchResult(out io.Writer, result bench.Result) {
+	client := result.Latencies()
+	server := result.ServerTimes()
+
+	t := tui.NewTable(out)
+	t.Headers("", "client", "server")
+	t.Row("p50", client.Percentile(50).String(), server.Percentile(50).String())
+	t.Row("p90", client.Percentile(90).String(), server.Percentile(90).String())
+	t.Row("p99", client.Percentile(99).String(), server.Percentile(99).String())
+	t.Row("max", client.Max().String(), server.Max().String())
+	t.Row("mean", client.Mean().String(), server.Mean().String())
+	_, _ = fmt.Fprintln(out, t.Render())
+
+	_, _ = fmt.Fprintf(out, "requests:   %d in %s\n", len(result.Samples), result.Elapsed.Round(time.Millisecond))
+	_, _ = fmt.Fprintf(out, "throughput: %.2f requests/s\n", result.Throughput())
+	_, _ = fmt.Fprintf(out, "errors:     %d (%.2f%%)\n", result.Errors(), 100*result.ErrorRate())
+
+	if len(client) > 0 {
+		_, _ = fmt.Fprintf(out, "\nclient latency histogram:\n")
+		client.Histogram(out, 10, 40)
+	}
+}
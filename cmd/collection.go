@@ -16,9 +16,12 @@ import (
 
 	"github.com/rockset/cli/completion"
 	"github.com/rockset/cli/config"
+	"github.com/rockset/cli/diff"
 	"github.com/rockset/cli/flag"
 	"github.com/rockset/cli/format"
+	"github.com/rockset/cli/graph"
 	"github.com/rockset/cli/sort"
+	"github.com/rockset/cli/wait"
 )
 
 func newDeleteCollectionCmd() *cobra.Command {
@@ -40,6 +43,12 @@ func newDeleteCollectionCmd() *cobra.Command {
 			ws, _ := cmd.Flags().GetString(flag.Workspace)
 			name := args[0]
 
+			warnBrokenDependents(ctx, cmd, rs, graph.Node{Kind: graph.Collection, Workspace: ws, Name: name})
+
+			if ok, err := confirmDelete(cmd, []string{fmt.Sprintf("collection %s.%s", ws, name)}); err != nil || !ok {
+				return err
+			}
+
 			err = rs.DeleteCollection(ctx, ws, name)
 			if err != nil {
 				return err
@@ -52,6 +61,7 @@ func newDeleteCollectionCmd() *cobra.Command {
 
 	cmd.Flags().StringP(flag.Workspace, flag.WorkspaceShort, flag.DefaultWorkspace, "workspace for the collection")
 	_ = cmd.RegisterFlagCompletionFunc(flag.Workspace, completion.Workspace(Version))
+	addDeleteFlags(&cmd)
 
 	return &cmd
 }
@@ -156,22 +166,25 @@ func newListCollectionsCmd() *cobra.Command {
 
 func newCreateCollectionCmd() *cobra.Command {
 	cmd := cobra.Command{
-		Use:         "collection NAME",
-		Aliases:     []string{"coll", "c"},
-		Short:       "create collection for use with the write API",
-		Long:        "create collection for use with the write API",
+		Use:     "collection NAME",
+		Aliases: []string{"coll", "c"},
+		Short:   "create collection for use with the write API",
+		Long: `create collection for use with the write API.
+
+To create a collection which ingests from a data source, use the collection command of the source,
+e.g. rockset create kafka collection`,
 		Annotations: group("collection"),
 		Args:        cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			name := args[0]
 			ws, _ := cmd.Flags().GetString(flag.Workspace)
-			retention, _ := cmd.Flags().GetDuration(flag.Retention)
-			transform, _ := cmd.Flags().GetString(flag.IngestTransformation)
-
 			input, _ := cmd.Flags().GetString("input")
 
-			options := getCommonCollectionFlags(cmd)
+			options, err := getCommonCollectionFlags(cmd)
+			if err != nil {
+				return err
+			}
 
 			rs, err := config.Client(cmd, Version)
 			if err != nil {
@@ -194,14 +207,8 @@ func newCreateCollectionCmd() *cobra.Command {
 				}
 
 				request.Name = &name
-				options = append(options, option.WithCollectionRequest(request))
-			}
-
-			if retention != 0 {
-				options = append(options, option.WithCollectionRetention(retention))
-			}
-			if transform != "" {
-				options = append(options, option.WithIngestTransformation(transform))
+				// the request replaces all options, so it needs to go first for the flags to override it
+				options = append([]option.CollectionOption{option.WithCollectionRequest(request)}, options...)
 			}
 
 			result, err := rs.CreateCollection(ctx, ws, name, options...)
@@ -225,62 +232,92 @@ func newCreateCollectionCmd() *cobra.Command {
 	return &cmd
 }
 
-func newCreateS3CollectionCmd() *cobra.Command {
+func newUpdateCollectionCmd() *cobra.Command {
 	cmd := cobra.Command{
-		Use:         "collection NAME",
-		Aliases:     []string{"coll", "c"},
-		Short:       "create S3 collection",
-		Long:        "create S3 collection",
-		Annotations: group("collection"),
-		Args:        cobra.ExactArgs(1),
+		Use:     "collection NAME",
+		Aliases: []string{"coll", "c"},
+		Short:   "update collection",
+		Long: `update the ingest transformation or description of a collection.
+
+The changes are shown and need to be confirmed before the collection is updated.
+The retention, sources and clustering key of a collection can't be changed after it is created.`,
+		Example: `	## update the ingest transformation and wait for the collection to be ready
+	rockset update collection --workspace prod -I ingest.sql --wait movies`,
+		Annotations:       group("collection"),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.Collection(Version),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
-			name := args[0]
 			ws, _ := cmd.Flags().GetString(flag.Workspace)
+			name := args[0]
+			out := cmd.OutOrStdout()
 
-			options := getCommonCollectionFlags(cmd)
-
-			integration, _ := cmd.Flags().GetString(flag.Integration)
-			bucket, _ := cmd.Flags().GetString(flag.Bucket)
-
-			var s3Opts []option.S3SourceOption
-			if region, _ := cmd.Flags().GetString(flag.Region); region != "" {
-				s3Opts = append(s3Opts, option.WithS3Region(region))
-			}
-			if pattern, _ := cmd.Flags().GetString(flag.Pattern); pattern != "" {
-				s3Opts = append(s3Opts, option.WithS3Pattern(pattern))
+			transformation, err := ingestTransformation(cmd)
+			if err != nil {
+				return err
 			}
 
-			options = append(options, option.WithS3Source(integration, bucket, option.WithJSONFormat(), s3Opts...))
-
 			rs, err := config.Client(cmd, Version)
 			if err != nil {
 				return err
 			}
 
-			result, err := rs.CreateCollection(ctx, ws, name, options...)
+			collection, err := rs.GetCollection(ctx, ws, name)
 			if err != nil {
 				return err
 			}
 
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "collection '%s.%s' is %s\n", ws, name, result.GetStatus())
+			var options []option.CollectionOption
+			if transformation != "" {
+				current := collection.GetFieldMappingQuery()
+				lines := diff.Text(current.GetSql(), transformation)
+				if diff.Changed(lines) {
+					_, _ = fmt.Fprintf(out, "ingest transformation:\n")
+					diff.Write(out, lines)
+					options = append(options, option.WithIngestTransformation(transformation))
+				}
+			}
 
-			return nil
-		},
-	}
+			if cmd.Flags().Changed(flag.Description) {
+				description, _ := cmd.Flags().GetString(flag.Description)
+				if description != collection.GetDescription() {
+					_, _ = fmt.Fprintf(out, "description: '%s' -> '%s'\n", collection.GetDescription(), description)
+					options = append(options, option.WithCollectionDescription(description))
+				}
+			}
 
-	cmd.Flags().String(flag.Integration, "", "integration name")
-	cmd.Flags().String(flag.Bucket, "", "S3 bucket")
-	cmd.Flags().String(flag.Pattern, "", "S3 pattern")
-	cmd.Flags().String(flag.Region, "", "AWS region of the S3 bucket")
-	cmd.Flags().String("source-format", "json", "data source format")
+			if len(options) == 0 {
+				_, _ = fmt.Fprintf(out, "collection '%s.%s' is unchanged\n", ws, name)
+				return nil
+			}
+
+			ok, err := confirm(cmd, "update", []string{fmt.Sprintf("collection %s.%s", ws, name)})
+			if err != nil {
+				return err
+			}
+			if !ok {
+				_, _ = fmt.Fprintf(out, "collection '%s.%s' not updated\n", ws, name)
+				return nil
+			}
 
-	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.Integration)
-	_ = cmd.RegisterFlagCompletionFunc(flag.Integration, completion.Integration(Version))
+			if _, err = rs.UpdateCollection(ctx, ws, name, options...); err != nil {
+				return fmt.Errorf("failed to update collection: %w", err)
+			}
+			_, _ = fmt.Fprintf(out, "collection '%s.%s' updated\n", ws, name)
 
-	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.Bucket)
+			return waitForCollection(ctx, cmd, rs, ws, name)
+		},
+	}
 
-	addCommonCollectionFlags(&cmd)
+	cmd.Flags().StringP(flag.Workspace, flag.WorkspaceShort, flag.DefaultWorkspace, "workspace for the collection")
+	_ = cmd.RegisterFlagCompletionFunc(flag.Workspace, completion.Workspace(Version))
+	cmd.Flags().String(flag.Description, "", "collection description")
+	cmd.Flags().String(flag.IngestTransformation, "", "ingest transformation SQL")
+	cmd.Flags().StringP(flag.IngestTransformationFile, "I", "", "read ingest transformation SQL from file")
+	_ = cobra.MarkFlagFilename(cmd.Flags(), flag.IngestTransformationFile, ".sql")
+	cmd.MarkFlagsMutuallyExclusive(flag.IngestTransformation, flag.IngestTransformationFile)
+	addConfirmFlags(&cmd, "update")
+	addWaitFlags(&cmd, "collection is ready")
 
 	return &cmd
 }
@@ -306,7 +343,10 @@ func newCreateSampleCollectionCmd() *cobra.Command {
 			from, _ := cmd.Flags().GetString(flag.Dataset)
 			ds := dataset.Sample(from)
 
-			options := getCommonCollectionFlags(cmd)
+			options, err := getCommonCollectionFlags(cmd)
+			if err != nil {
+				return err
+			}
 			pattern := dataset.Lookup(ds)
 			if pattern == "" {
 				datasets := []string{string(dataset.Movies), string(dataset.MovieRatings)}
@@ -419,16 +459,14 @@ func Map[IN, OUT any](array []IN, fn func(IN) OUT) []OUT {
 }
 
 func waitForCollection(ctx context.Context, cmd *cobra.Command, rs *rockset.RockClient, ws, name string) error {
-	wait, _ := cmd.Flags().GetBool(flag.Wait)
-
-	if wait {
-		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "waiting for collection '%s.%s' to be READY\n", ws, name)
-		if err := rs.Wait.UntilCollectionReady(ctx, ws, name); err != nil {
-			return fmt.Errorf("failed to wait for %s.%s to be ready: %v", ws, name, err)
-		}
-	}
-
-	return nil
+	return waitFor(ctx, cmd, wait.Target{
+		Resource: fmt.Sprintf("collection %s.%s", ws, name),
+		Ready:    []string{option.CollectionStatusReady.String()},
+		State: func(ctx context.Context) (string, error) {
+			c, err := rs.GetCollection(ctx, ws, name)
+			return c.GetStatus(), err
+		},
+	})
 }
 
 func addCommonCollectionFlags(cmd *cobra.Command) {
@@ -439,11 +477,13 @@ func addCommonCollectionFlags(cmd *cobra.Command) {
 	cmd.Flags().Duration(flag.Retention, 0, "collection retention")
 
 	cmd.Flags().String(flag.IngestTransformation, "", "ingest transformation SQL")
-	cmd.Flags().StringP("ingest-transformation-file", "I", "", "read ingest transformation SQL from file")
-	cmd.Flags().Bool(flag.Wait, false, "wait until collection is ready")
+	cmd.Flags().StringP(flag.IngestTransformationFile, "I", "", "read ingest transformation SQL from file")
+	_ = cobra.MarkFlagFilename(cmd.Flags(), flag.IngestTransformationFile, ".sql")
+	cmd.MarkFlagsMutuallyExclusive(flag.IngestTransformation, flag.IngestTransformationFile)
+	addWaitFlags(cmd, "collection is ready")
 }
 
-func getCommonCollectionFlags(cmd *cobra.Command) []option.CollectionOption {
+func getCommonCollectionFlags(cmd *cobra.Command) ([]option.CollectionOption, error) {
 	var options []option.CollectionOption
 
 	if retention, _ := cmd.Flags().GetDuration(flag.Retention); retention != 0 {
@@ -457,7 +497,30 @@ func getCommonCollectionFlags(cmd *cobra.Command) []option.CollectionOption {
 		options = append(options, option.WithStorageCompressionType(option.StorageCompressionType(compression)))
 	}
 
-	return options
+	transformation, err := ingestTransformation(cmd)
+	if err != nil {
+		return nil, err
+	}
+	if transformation != "" {
+		options = append(options, option.WithIngestTransformation(transformation))
+	}
+
+	return options, nil
+}
+
+// ingestTransformation returns the ingest transformation SQL from either the flag or the file
+func ingestTransformation(cmd *cobra.Command) (string, error) {
+	if file, _ := cmd.Flags().GetString(flag.IngestTransformationFile); file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("failed to read ingest transformation: %w", err)
+		}
+		return string(data), nil
+	}
+
+	transformation, _ := cmd.Flags().GetString(flag.IngestTransformation)
+
+	return transformation, nil
 }
 
 func translate(in openapi.Collection) openapi.CreateCollectionRequest {
@@ -0,0 +1,512 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rockset/rockset-go-client/openapi"
+	"github.com/rockset/rockset-go-client/option"
+	"github.com/spf13/cobra"
+
+	"github.com/rockset/cli/completion"
+	"github.com/rockset/cli/config"
+	"github.com/rockset/cli/flag"
+)
+
+// source formats which can be used with --source-format
+const (
+	formatAuto    = "auto"
+	formatAvro    = "avro"
+	formatCSV     = "csv"
+	formatJSON    = "json"
+	formatParquet = "parquet"
+	formatXML     = "xml"
+)
+
+// sourceFormatsAnnotation is the --source-format flag annotation with the formats the source supports
+const sourceFormatsAnnotation = "rockset_source_formats"
+
+// fileFormats are the formats of sources which read files, e.g. S3
+var fileFormats = []string{formatJSON, formatCSV, formatParquet, formatXML, formatAuto}
+
+// streamFormats are the formats of sources which read messages, e.g. Kafka
+var streamFormats = []string{formatJSON, formatAvro}
+
+func newCreateS3CollectionCmd() *cobra.Command {
+	cmd := sourceCollectionCmd("S3", func(cmd *cobra.Command, format option.Format) (option.CollectionOption, error) {
+		integration, _ := cmd.Flags().GetString(flag.Integration)
+		bucket, _ := cmd.Flags().GetString(flag.Bucket)
+
+		var opts []option.S3SourceOption
+		if region, _ := cmd.Flags().GetString(flag.Region); region != "" {
+			opts = append(opts, option.WithS3Region(region))
+		}
+		if prefix, _ := cmd.Flags().GetString(flag.Prefix); prefix != "" {
+			opts = append(opts, option.WithS3Prefix(prefix))
+		}
+		if pattern, _ := cmd.Flags().GetString(flag.Pattern); pattern != "" {
+			opts = append(opts, option.WithS3Pattern(pattern))
+		}
+
+		return option.WithS3Source(integration, bucket, format, opts...), nil
+	})
+
+	cmd.Flags().String(flag.Bucket, "", "S3 bucket")
+	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.Bucket)
+	cmd.Flags().String(flag.Prefix, "", "only ingest objects with this prefix")
+	cmd.Flags().String(flag.Pattern, "", "only ingest objects matching this glob pattern")
+	cmd.Flags().String(flag.Region, "", "AWS region of the S3 bucket")
+	addSourceFormatFlags(cmd, fileFormats)
+
+	return cmd
+}
+
+func newCreateKafkaCollectionCmd() *cobra.Command {
+	cmd := sourceCollectionCmd("Kafka", func(cmd *cobra.Command, format option.Format) (option.CollectionOption, error) {
+		integration, _ := cmd.Flags().GetString(flag.Integration)
+		topic, _ := cmd.Flags().GetString(flag.Topic)
+		offset, _ := cmd.Flags().GetString(flag.StartingOffset)
+
+		start := option.KafkaStartingOffset(strings.ToUpper(offset))
+		if start != option.KafkaStartingOffsetEarliest && start != option.KafkaStartingOffsetLatest {
+			return nil, fmt.Errorf("--%s must be earliest or latest", flag.StartingOffset)
+		}
+
+		var opts []option.KafkaSourceOption
+		if group, _ := cmd.Flags().GetString(flag.ConsumerGroup); group != "" {
+			opts = append(opts, option.WithKafkaConsumerGroupID(group))
+		}
+		v3, _ := cmd.Flags().GetBool(flag.V3)
+		if !cmd.Flags().Changed(flag.V3) {
+			var err error
+			if v3, err = kafkaIntegrationUsesV3(cmd, integration); err != nil {
+				return nil, err
+			}
+		}
+		if v3 {
+			opts = append(opts, option.WithKafkaSourceV3())
+		}
+
+		return option.WithKafkaSource(integration, topic, start, format, opts...), nil
+	})
+
+	cmd.Flags().String(flag.Topic, "", "Kafka topic")
+	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.Topic)
+	cmd.Flags().String(flag.StartingOffset, "latest", "where to start reading the topic, earliest or latest")
+	cmd.Flags().String(flag.ConsumerGroup, "", "Kafka consumer group id")
+	cmd.Flags().Bool(flag.V3, false, "use the v3 Kafka connector, defaults to the connector of the integration")
+	addSourceFormatFlags(cmd, streamFormats)
+
+	return cmd
+}
+
+// kafkaIntegrationUsesV3 returns true if the Kafka integration uses the v3 connector,
+// as the collection has to use the same connector
+func kafkaIntegrationUsesV3(cmd *cobra.Command, name string) (bool, error) {
+	rs, err := config.Client(cmd, Version)
+	if err != nil {
+		return false, err
+	}
+
+	integration, err := rs.GetIntegration(cmd.Context(), name)
+	if err != nil {
+		return false, fmt.Errorf("failed to get integration %s: %w", name, err)
+	}
+	kafka := integration.GetKafka()
+
+	return kafka.GetUseV3(), nil
+}
+
+func newCreateKinesisCollectionCmd() *cobra.Command {
+	cmd := sourceCollectionCmd("Kinesis", func(cmd *cobra.Command, format option.Format) (option.CollectionOption, error) {
+		integration, _ := cmd.Flags().GetString(flag.Integration)
+		stream, _ := cmd.Flags().GetString(flag.Stream)
+		region, _ := cmd.Flags().GetString(flag.Region)
+		offset, _ := cmd.Flags().GetString(flag.StartingOffset)
+		keys, _ := cmd.Flags().GetStringSlice(flag.DMSPrimaryKey)
+
+		return withSource(integration, format, openapi.Source{
+			Kinesis: &openapi.SourceKinesis{
+				StreamName:        stream,
+				AwsRegion:         optionalString(region),
+				OffsetResetPolicy: optionalString(strings.ToUpper(offset)),
+				DmsPrimaryKey:     keys,
+			},
+		}), nil
+	})
+
+	cmd.Flags().String(flag.Stream, "", "Kinesis stream name")
+	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.Stream)
+	cmd.Flags().String(flag.Region, "", "AWS region of the stream")
+	cmd.Flags().String(flag.StartingOffset, "latest", "where to start reading the stream, earliest or latest")
+	cmd.Flags().StringSlice(flag.DMSPrimaryKey, nil, "primary key fields when the stream contains DMS records")
+	addSourceFormatFlags(cmd, streamFormats)
+
+	return cmd
+}
+
+func newCreateDynamoDBCollectionCmd() *cobra.Command {
+	cmd := sourceCollectionCmd("DynamoDB", func(cmd *cobra.Command, _ option.Format) (option.CollectionOption, error) {
+		integration, _ := cmd.Flags().GetString(flag.Integration)
+		table, _ := cmd.Flags().GetString(flag.Table)
+		region, _ := cmd.Flags().GetString(flag.Region)
+		scan, _ := cmd.Flags().GetBool(flag.UseScanAPI)
+
+		src := openapi.SourceDynamoDb{
+			TableName: table,
+			AwsRegion: optionalString(region),
+		}
+		if cmd.Flags().Changed(flag.UseScanAPI) {
+			src.UseScanApi = &scan
+		}
+		if rcu, _ := cmd.Flags().GetInt64(flag.RCU); rcu > 0 {
+			src.Rcu = &rcu
+		}
+
+		return withSource(integration, nil, openapi.Source{Dynamodb: &src}), nil
+	})
+
+	cmd.Flags().String(flag.Table, "", "DynamoDB table name")
+	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.Table)
+	cmd.Flags().String(flag.Region, "", "AWS region of the table")
+	cmd.Flags().Int64(flag.RCU, 0, "maximum read capacity units to use for the initial scan")
+	cmd.Flags().Bool(flag.UseScanAPI, false, "use the scan API for the initial load instead of an export")
+
+	return cmd
+}
+
+func newCreateMongoDBCollectionCmd() *cobra.Command {
+	cmd := sourceCollectionCmd("MongoDB", func(cmd *cobra.Command, _ option.Format) (option.CollectionOption, error) {
+		integration, _ := cmd.Flags().GetString(flag.Integration)
+		database, _ := cmd.Flags().GetString(flag.Database)
+		collection, _ := cmd.Flags().GetString(flag.Collection)
+
+		src := openapi.SourceMongoDb{
+			DatabaseName:   database,
+			CollectionName: collection,
+		}
+		if cmd.Flags().Changed(flag.RetrieveFullDocument) {
+			full, _ := cmd.Flags().GetBool(flag.RetrieveFullDocument)
+			src.RetrieveFullDocument = &full
+		}
+
+		return withSource(integration, nil, openapi.Source{Mongodb: &src}), nil
+	})
+
+	cmd.Flags().String(flag.Database, "", "MongoDB database name")
+	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.Database)
+	cmd.Flags().String(flag.Collection, "", "MongoDB collection name")
+	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.Collection)
+	cmd.Flags().Bool(flag.RetrieveFullDocument, false, "retrieve the full document on updates")
+
+	return cmd
+}
+
+func newCreateGCSCollectionCmd() *cobra.Command {
+	cmd := sourceCollectionCmd("GCS", func(cmd *cobra.Command, format option.Format) (option.CollectionOption, error) {
+		integration, _ := cmd.Flags().GetString(flag.Integration)
+		bucket, _ := cmd.Flags().GetString(flag.Bucket)
+		prefix, _ := cmd.Flags().GetString(flag.Prefix)
+		pattern, _ := cmd.Flags().GetString(flag.Pattern)
+
+		return withSource(integration, format, openapi.Source{
+			Gcs: &openapi.SourceGcs{
+				Bucket:  &bucket,
+				Prefix:  optionalString(prefix),
+				Pattern: optionalString(pattern),
+			},
+		}), nil
+	})
+
+	cmd.Flags().String(flag.Bucket, "", "GCS bucket")
+	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.Bucket)
+	cmd.Flags().String(flag.Prefix, "", "only ingest objects with this prefix")
+	cmd.Flags().String(flag.Pattern, "", "only ingest objects matching this glob pattern")
+	addSourceFormatFlags(cmd, fileFormats)
+
+	return cmd
+}
+
+func newCreateAzureCollectionCmd() *cobra.Command {
+	cmd := sourceCollectionCmd("Azure Blob Storage",
+		func(cmd *cobra.Command, format option.Format) (option.CollectionOption, error) {
+			integration, _ := cmd.Flags().GetString(flag.Integration)
+			container, _ := cmd.Flags().GetString(flag.Container)
+			prefix, _ := cmd.Flags().GetString(flag.Prefix)
+			pattern, _ := cmd.Flags().GetString(flag.Pattern)
+
+			return withSource(integration, format, openapi.Source{
+				AzureBlobStorage: &openapi.SourceAzureBlobStorage{
+					Container: &container,
+					Prefix:    optionalString(prefix),
+					Pattern:   optionalString(pattern),
+				},
+			}), nil
+		})
+
+	cmd.Flags().String(flag.Container, "", "Azure Blob Storage container")
+	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.Container)
+	cmd.Flags().String(flag.Prefix, "", "only ingest blobs with this prefix")
+	cmd.Flags().String(flag.Pattern, "", "only ingest blobs matching this glob pattern")
+	addSourceFormatFlags(cmd, fileFormats)
+
+	return cmd
+}
+
+func newCreateSnowflakeCollectionCmd() *cobra.Command {
+	cmd := sourceCollectionCmd("Snowflake", func(cmd *cobra.Command, _ option.Format) (option.CollectionOption, error) {
+		integration, _ := cmd.Flags().GetString(flag.Integration)
+		database, _ := cmd.Flags().GetString(flag.Database)
+		schema, _ := cmd.Flags().GetString(flag.Schema)
+		table, _ := cmd.Flags().GetString(flag.Table)
+		warehouse, _ := cmd.Flags().GetString(flag.Warehouse)
+
+		return withSource(integration, nil, openapi.Source{
+			Snowflake: &openapi.SourceSnowflake{
+				Database:  database,
+				Schema:    schema,
+				TableName: table,
+				Warehouse: optionalString(warehouse),
+			},
+		}), nil
+	})
+
+	cmd.Flags().String(flag.Database, "", "Snowflake database")
+	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.Database)
+	cmd.Flags().String(flag.Schema, "", "Snowflake schema")
+	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.Schema)
+	cmd.Flags().String(flag.Table, "", "Snowflake table")
+	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.Table)
+	cmd.Flags().String(flag.Warehouse, "", "Snowflake warehouse, defaults to the one of the integration")
+
+	return cmd
+}
+
+// sourceFn returns the source option for a collection, using the format from the --source-format flags
+type sourceFn func(cmd *cobra.Command, format option.Format) (option.CollectionOption, error)
+
+// sourceCollectionCmd returns a command which creates a collection using an integration, with the common collection
+// flags, and the source flags have to be added by the caller
+func sourceCollectionCmd(source string, fn sourceFn) *cobra.Command {
+	cmd := cobra.Command{
+		Use:         "collection NAME",
+		Aliases:     []string{"coll", "c"},
+		Short:       fmt.Sprintf("create %s collection", source),
+		Long:        fmt.Sprintf("create a collection which ingests data from %s", source),
+		Annotations: group("collection"),
+		Args:        cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			name := args[0]
+			ws, _ := cmd.Flags().GetString(flag.Workspace)
+
+			options, err := getCommonCollectionFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			var format option.Format
+			if cmd.Flags().Lookup(flag.SourceFormat) != nil {
+				if format, err = sourceFormat(cmd); err != nil {
+					return err
+				}
+			}
+
+			src, err := fn(cmd, format)
+			if err != nil {
+				return err
+			}
+			options = append(options, src)
+
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			result, err := rs.CreateCollection(ctx, ws, name, options...)
+			if err != nil {
+				return fmt.Errorf("failed to create collection: %w", err)
+			}
+
+			if err = waitForCollection(ctx, cmd, rs, ws, name); err != nil {
+				return err
+			}
+			// get the current status, as it has changed if we waited for the collection
+			if c, err := rs.GetCollection(ctx, ws, name); err == nil {
+				result = c
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "collection '%s.%s' is %s\n", ws, name, result.GetStatus())
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flag.Integration, "", "integration name")
+	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.Integration)
+	_ = cmd.RegisterFlagCompletionFunc(flag.Integration, completion.Integration(Version))
+
+	addCommonCollectionFlags(&cmd)
+
+	return &cmd
+}
+
+// withSource adds the source to the collection, the format is optional as not all sources use it
+func withSource(integration string, format option.Format, src openapi.Source) option.CollectionOption {
+	src.IntegrationName = &integration
+	if format != nil {
+		var fp openapi.FormatParams
+		format(&fp)
+		src.FormatParams = &fp
+	}
+
+	return func(o *openapi.CreateCollectionRequest) {
+		o.Sources = append(o.Sources, src)
+	}
+}
+
+func addSourceFormatFlags(cmd *cobra.Command, formats []string) {
+	cmd.Flags().String(flag.SourceFormat, formatJSON,
+		fmt.Sprintf("data source format, one of: %s", strings.Join(formats, ", ")))
+	_ = cmd.RegisterFlagCompletionFunc(flag.SourceFormat, cobra.FixedCompletions(formats, cobra.ShellCompDirectiveNoFileComp))
+	_ = cmd.Flags().SetAnnotation(flag.SourceFormat, sourceFormatsAnnotation, formats)
+
+	if slices.Contains(formats, formatCSV) {
+		cmd.Flags().StringSlice(flag.CSVColumns, nil, "CSV columns as NAME:TYPE, where the type is one of: "+
+			strings.Join(csvColumnTypes(), ", "))
+		cmd.Flags().Bool(flag.CSVHeader, false, "use the first line of the CSV as the column names")
+		cmd.Flags().String(flag.CSVSeparator, "", "CSV separator, defaults to ,")
+		cmd.Flags().String(flag.CSVQuote, "", "CSV quote character, defaults to \"")
+		cmd.Flags().String(flag.CSVEscape, "", "CSV escape character, defaults to \\")
+	}
+
+	if slices.Contains(formats, formatXML) {
+		cmd.Flags().String(flag.XMLRootTag, "", "XML tag of the root element")
+		cmd.Flags().String(flag.XMLDocTag, "", "XML tag of the elements which are documents")
+		cmd.Flags().String(flag.XMLValueTag, "", "XML tag used for the value of elements with attributes")
+		cmd.Flags().String(flag.XMLAttributePrefix, "", "prefix for XML attributes")
+	}
+
+	if slices.Contains(formats, formatCSV) || slices.Contains(formats, formatXML) {
+		cmd.Flags().String(flag.Encoding, "", "character encoding of CSV or XML data, defaults to UTF-8")
+	}
+}
+
+// sourceFormat returns the format from the --source-format flags
+func sourceFormat(cmd *cobra.Command) (option.Format, error) {
+	f, _ := cmd.Flags().GetString(flag.SourceFormat)
+	f = strings.ToLower(f)
+
+	formats := cmd.Flags().Lookup(flag.SourceFormat).Annotations[sourceFormatsAnnotation]
+	if !slices.Contains(formats, f) {
+		return nil, fmt.Errorf("unsupported source format %s, must be one of: %s", f, strings.Join(formats, ", "))
+	}
+
+	encoding, _ := cmd.Flags().GetString(flag.Encoding)
+
+	switch f {
+	case formatJSON:
+		return option.WithJSONFormat(), nil
+	case formatAvro:
+		return func(fp *openapi.FormatParams) {
+			fp.Avro = map[string]any{}
+		}, nil
+	case formatParquet, formatAuto:
+		// parquet is auto-detected
+		return option.WithAutoFormat(), nil
+	case formatXML:
+		var xml openapi.XmlParams
+		for flagName, field := range map[string]**string{
+			flag.XMLRootTag:         &xml.RootTag,
+			flag.XMLDocTag:          &xml.DocTag,
+			flag.XMLValueTag:        &xml.ValueTag,
+			flag.XMLAttributePrefix: &xml.AttributePrefix,
+		} {
+			v, _ := cmd.Flags().GetString(flagName)
+			*field = optionalString(v)
+		}
+		xml.Encoding = optionalString(encoding)
+		return option.WithXMLFormat(xml), nil
+	case formatCSV:
+		columns, _ := cmd.Flags().GetStringSlice(flag.CSVColumns)
+		names, types, err := ParseCSVColumns(columns)
+		if err != nil {
+			return nil, err
+		}
+
+		header, _ := cmd.Flags().GetBool(flag.CSVHeader)
+		if len(columns) == 0 && !header {
+			return nil, fmt.Errorf("the CSV columns must be specified using --%s or --%s", flag.CSVColumns,
+				flag.CSVHeader)
+		}
+
+		var opts []option.CSV
+		if header {
+			opts = append(opts, option.WithFirstLineAsColumnNames())
+		}
+		if s, _ := cmd.Flags().GetString(flag.CSVSeparator); s != "" {
+			opts = append(opts, option.WithSeparator(s))
+		}
+		if q, _ := cmd.Flags().GetString(flag.CSVQuote); q != "" {
+			opts = append(opts, option.WithQuoteChar(q))
+		}
+		if e, _ := cmd.Flags().GetString(flag.CSVEscape); e != "" {
+			opts = append(opts, option.WithEscapeChar(e))
+		}
+		if encoding != "" {
+			opts = append(opts, option.WithEncoding(encoding))
+		}
+
+		return option.WithCSVFormat(names, types, opts...), nil
+	}
+
+	return nil, fmt.Errorf("unsupported source format %s", f)
+}
+
+// ParseCSVColumns parses a list of NAME:TYPE column definitions
+func ParseCSVColumns(columns []string) ([]string, []option.ColumnType, error) {
+	names := make([]string, len(columns))
+	types := make([]option.ColumnType, len(columns))
+
+	for i, c := range columns {
+		name, typ, found := strings.Cut(c, ":")
+		if !found || name == "" {
+			return nil, nil, fmt.Errorf("invalid CSV column %s, must be NAME:TYPE", c)
+		}
+
+		ct := option.ColumnTypeUnknown
+		for t := option.ColumnTypeBoolean; t <= option.ColumnTypeInt; t++ {
+			if strings.EqualFold(t.String(), typ) {
+				ct = t
+				break
+			}
+		}
+		if ct == option.ColumnTypeUnknown {
+			return nil, nil, fmt.Errorf("invalid type %s for CSV column %s, must be one of: %s", typ, name,
+				strings.Join(csvColumnTypes(), ", "))
+		}
+
+		names[i] = name
+		types[i] = ct
+	}
+
+	return names, types, nil
+}
+
+func csvColumnTypes() []string {
+	var list []string
+	for t := option.ColumnTypeBoolean; t <= option.ColumnTypeInt; t++ {
+		list = append(list, strings.ToLower(t.String()))
+	}
+
+	return list
+}
+
+// optionalString returns nil for an empty string, so it is left out of requests
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
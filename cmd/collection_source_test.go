@@ -0,0 +1,28 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/rockset/rockset-go-client/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/cli/cmd"
+)
+
+func TestParseCSVColumns(t *testing.T) {
+	names, types, err := cmd.ParseCSVColumns([]string{"id:int", "name:STRING", "created:timestamp"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "name", "created"}, names)
+	assert.Equal(t, []option.ColumnType{option.ColumnTypeInt, option.ColumnTypeString, option.ColumnTypeTimestamp},
+		types)
+
+	_, _, err = cmd.ParseCSVColumns([]string{"id"})
+	assert.ErrorContains(t, err, "NAME:TYPE")
+
+	_, _, err = cmd.ParseCSVColumns([]string{"id:uuid"})
+	assert.ErrorContains(t, err, "must be one of")
+
+	_, _, err = cmd.ParseCSVColumns([]string{"id:unknown"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,340 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/rockset/rockset-go-client/openapi"
+	"github.com/spf13/cobra"
+
+	"github.com/rockset/cli/completion"
+	"github.com/rockset/cli/config"
+	"github.com/rockset/cli/diff"
+	"github.com/rockset/cli/flag"
+	"github.com/rockset/cli/lookup"
+	"github.com/rockset/cli/tui"
+)
+
+func newCompareQueryCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "query [SQL]",
+		Short: "compare query results",
+		Long: `execute the same query on two virtual instances or two contexts, and compare the results.
+
+Rows are compared ignoring their order, unless --ordered is used, or matched using the --key field.`,
+		Example: `	## compare the results on two virtual instances
+	rockset compare query --vi main --vi analytics "SELECT * FROM movies"
+
+	## compare the results in two contexts, matching rows by their _id
+	rockset compare query --context staging --context prod --key _id --file query.sql`,
+		Args:        cobra.RangeArgs(0, 1),
+		Annotations: group("query"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			file, _ := cmd.Flags().GetString(flag.File)
+			vis, _ := cmd.Flags().GetStringArray(flag.VI)
+			contexts, _ := cmd.Flags().GetStringArray(flag.Context)
+			key, _ := cmd.Flags().GetString(flag.Key)
+			ordered, _ := cmd.Flags().GetBool(flag.Ordered)
+			maxDiffs, _ := cmd.Flags().GetInt(flag.MaxDiffs)
+
+			sql, err := sqlFromArgsOrFile(args, file)
+			if err != nil {
+				return err
+			}
+
+			targets, err := compareTargets(contexts, vis)
+			if err != nil {
+				return err
+			}
+
+			for i, t := range targets {
+				rs, err := config.ContextClient(t.Context, Version)
+				if err != nil {
+					return err
+				}
+
+				var resp openapi.QueryResponse
+				start := time.Now()
+				if t.VI == "" {
+					resp, err = rs.Query(ctx, sql)
+				} else {
+					var id string
+					if id, err = lookup.VirtualInstanceNameOrIDtoID(ctx, rs, t.VI); err != nil {
+						return err
+					}
+					start = time.Now()
+					resp, err = rs.ExecuteQueryOnVirtualInstance(ctx, id, sql)
+				}
+				targets[i].Latency = time.Since(start)
+
+				if targets[i].ServerTime, err = queryServerTime(resp, err); err != nil {
+					return fmt.Errorf("query on %s failed: %w", t, err)
+				}
+				targets[i].Rows = resp.Results
+			}
+
+			out := cmd.OutOrStdout()
+			showCompareTargets(out, targets)
+
+			c, err := CompareResults(targets[0].Rows, targets[1].Rows, key, ordered)
+			if err != nil {
+				return err
+			}
+			showResultComparison(out, c, targets, maxDiffs)
+
+			if !c.Equal() {
+				return fmt.Errorf("query results differ")
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flag.File, "", "read SQL from file")
+	_ = cobra.MarkFlagFilename(cmd.Flags(), flag.File, ".sql")
+
+	// this shadows the global --context flag, so it can be given twice
+	cmd.Flags().StringArray(flag.Context, nil, "configuration context to execute the query in, can be given twice")
+	cmd.Flags().StringArray(flag.VI, nil, "virtual instance to execute the query on, can be given twice")
+	_ = cmd.RegisterFlagCompletionFunc(flag.VI, completion.VirtualInstance(Version))
+
+	cmd.Flags().String(flag.Key, "", "match rows using this field instead of comparing all rows")
+	cmd.Flags().Bool(flag.Ordered, false, "the rows must be in the same order")
+	cmd.Flags().Int(flag.MaxDiffs, 20, "maximum number of differing rows to show")
+	cmd.MarkFlagsMutuallyExclusive(flag.Key, flag.Ordered)
+
+	return &cmd
+}
+
+type compareTarget struct {
+	Context    string
+	VI         string
+	Rows       []map[string]any
+	Latency    time.Duration
+	ServerTime time.Duration
+}
+
+func (t compareTarget) String() string {
+	ctx := t.Context
+	if ctx == "" {
+		ctx = "current context"
+	}
+	if t.VI == "" {
+		return ctx
+	}
+
+	return fmt.Sprintf("%s/%s", ctx, t.VI)
+}
+
+// compareTargets pairs up the contexts and virtual instances, if only one is given it is used for both targets
+func compareTargets(contexts, vis []string) ([]compareTarget, error) {
+	if len(contexts) > 2 || len(vis) > 2 {
+		return nil, fmt.Errorf("at most two contexts and two virtual instances can be compared")
+	}
+	if len(contexts) < 2 && len(vis) < 2 {
+		return nil, fmt.Errorf("specify either two contexts or two virtual instances to compare")
+	}
+
+	pick := func(list []string, i int) string {
+		switch len(list) {
+		case 0:
+			return ""
+		case 1:
+			return list[0]
+		default:
+			return list[i]
+		}
+	}
+
+	targets := make([]compareTarget, 2)
+	for i := range targets {
+		targets[i] = compareTarget{Context: pick(contexts, i), VI: pick(vis, i)}
+	}
+
+	return targets, nil
+}
+
+// ResultComparison is the outcome of comparing two query results
+type ResultComparison struct {
+	Matching  int
+	OnlyLeft  []string
+	OnlyRight []string
+	// Changed are the keys of the rows which differ, only used when comparing by key
+	Changed []string
+}
+
+func (c ResultComparison) Equal() bool {
+	return len(c.OnlyLeft) == 0 && len(c.OnlyRight) == 0 && len(c.Changed) == 0
+}
+
+// CompareResults compares two query results, either matching rows by the key field, in order,
+// or ignoring the order of the rows
+func CompareResults(left, right []map[string]any, key string, ordered bool) (ResultComparison, error) {
+	var c ResultComparison
+
+	if key != "" {
+		l, err := keyedRows(left, key)
+		if err != nil {
+			return c, err
+		}
+		r, err := keyedRows(right, key)
+		if err != nil {
+			return c, err
+		}
+
+		for k, row := range l.rows {
+			other, found := r.rows[k]
+			switch {
+			case !found:
+				c.OnlyLeft = append(c.OnlyLeft, row)
+			case other != row:
+				c.Changed = append(c.Changed, k)
+			default:
+				c.Matching++
+			}
+		}
+		for k, row := range r.rows {
+			if _, found := l.rows[k]; !found {
+				c.OnlyRight = append(c.OnlyRight, row)
+			}
+		}
+
+		// map iteration order is random, so present the rows in the order they were returned
+		c.OnlyLeft = l.ordered(c.OnlyLeft)
+		c.OnlyRight = r.ordered(c.OnlyRight)
+		c.Changed = l.orderedKeys(c.Changed)
+
+		return c, nil
+	}
+
+	l, err := canonicalRows(left, ordered)
+	if err != nil {
+		return c, err
+	}
+	r, err := canonicalRows(right, ordered)
+	if err != nil {
+		return c, err
+	}
+
+	// canonicalRows sorts the rows unless they are ordered, so a diff of them works in both cases
+	for _, line := range diff.Lines(l, r) {
+		switch line.Op {
+		case diff.Equal:
+			c.Matching++
+		case diff.Removed:
+			c.OnlyLeft = append(c.OnlyLeft, line.Text)
+		case diff.Added:
+			c.OnlyRight = append(c.OnlyRight, line.Text)
+		}
+	}
+
+	return c, nil
+}
+
+type keyed struct {
+	keys []string
+	rows map[string]string
+}
+
+func keyedRows(rows []map[string]any, key string) (keyed, error) {
+	canonical, err := canonicalRows(rows, true)
+	if err != nil {
+		return keyed{}, err
+	}
+
+	k := keyed{rows: make(map[string]string, len(rows))}
+	for i, row := range rows {
+		v, found := row[key]
+		if !found {
+			return k, fmt.Errorf("row %d doesn't have the key field %s", i, key)
+		}
+
+		id := fmt.Sprintf("%v", v)
+		if _, dup := k.rows[id]; dup {
+			return k, fmt.Errorf("key %s = %s isn't unique", key, id)
+		}
+		k.keys = append(k.keys, id)
+		k.rows[id] = canonical[i]
+	}
+
+	return k, nil
+}
+
+func (k keyed) ordered(rows []string) []string {
+	set := make(map[string]bool, len(rows))
+	for _, r := range rows {
+		set[r] = true
+	}
+
+	var result []string
+	for _, id := range k.keys {
+		if set[k.rows[id]] {
+			result = append(result, k.rows[id])
+		}
+	}
+
+	return result
+}
+
+func (k keyed) orderedKeys(keys []string) []string {
+	set := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		set[key] = true
+	}
+
+	var result []string
+	for _, id := range k.keys {
+		if set[id] {
+			result = append(result, id)
+		}
+	}
+
+	return result
+}
+
+func showCompareTargets(out io.Writer, targets []compareTarget) {
+	t := tui.NewTable(out)
+	t.Headers("", "target", "rows", "client latency", "server latency")
+	for i, target := range targets {
+		t.Row(fmt.Sprintf("%d", i+1), target.String(), fmt.Sprintf("%d", len(target.Rows)),
+			target.Latency.Round(time.Millisecond).String(), target.ServerTime.String())
+	}
+	_, _ = fmt.Fprintln(out, t.Render())
+	_, _ = fmt.Fprintf(out, "latency difference: client %s, server %s\n",
+		(targets[1].Latency - targets[0].Latency).Round(time.Millisecond),
+		targets[1].ServerTime-targets[0].ServerTime)
+}
+
+func showResultComparison(out io.Writer, c ResultComparison, targets []compareTarget, maxDiffs int) {
+	if c.Equal() {
+		_, _ = fmt.Fprintf(out, "\nresults match (%d rows)\n", c.Matching)
+		return
+	}
+
+	_, _ = fmt.Fprintf(out, "\nresults differ: %d matching, %d only in %s, %d only in %s",
+		c.Matching, len(c.OnlyLeft), targets[0], len(c.OnlyRight), targets[1])
+	if len(c.Changed) > 0 {
+		_, _ = fmt.Fprintf(out, ", %d changed", len(c.Changed))
+	}
+	_, _ = fmt.Fprintln(out)
+
+	var shown int
+	show := func(prefix string, rows []string) {
+		for _, r := range rows {
+			if shown >= maxDiffs {
+				return
+			}
+			_, _ = fmt.Fprintf(out, "%s %s\n", prefix, r)
+			shown++
+		}
+	}
+	show("-", c.OnlyLeft)
+	show("+", c.OnlyRight)
+	show("~", c.Changed)
+
+	if total := len(c.OnlyLeft) + len(c.OnlyRight) + len(c.Changed); total > shown {
+		_, _ = fmt.Fprintf(out, "... %d more\n", total-shown)
+	}
+}
@@ -0,0 +1,46 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/cli/cmd"
+)
+
+func TestCompareResults(t *testing.T) {
+	left := []map[string]any{
+		{"_id": "a", "n": float64(1)},
+		{"_id": "b", "n": float64(2)},
+		{"_id": "c", "n": float64(3)},
+	}
+	right := []map[string]any{
+		{"_id": "d", "n": float64(4)},
+		{"_id": "b", "n": float64(20)},
+		{"_id": "a", "n": float64(1)},
+	}
+
+	c, err := cmd.CompareResults(left, left[:], "", false)
+	require.NoError(t, err)
+	assert.True(t, c.Equal())
+	assert.Equal(t, 3, c.Matching)
+
+	c, err = cmd.CompareResults(left, []map[string]any{left[2], left[1], left[0]}, "", false)
+	require.NoError(t, err)
+	assert.True(t, c.Equal())
+
+	c, err = cmd.CompareResults(left, []map[string]any{left[2], left[1], left[0]}, "", true)
+	require.NoError(t, err)
+	assert.False(t, c.Equal())
+
+	c, err = cmd.CompareResults(left, right, "_id", false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, c.Matching)
+	assert.Equal(t, []string{`{"_id":"c","n":3}`}, c.OnlyLeft)
+	assert.Equal(t, []string{`{"_id":"d","n":4}`}, c.OnlyRight)
+	assert.Equal(t, []string{"b"}, c.Changed)
+
+	_, err = cmd.CompareResults(left, right, "missing", false)
+	assert.Error(t, err)
+}
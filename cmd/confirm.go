@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/rockset/cli/flag"
+	"github.com/rockset/cli/tui"
+)
+
+// addConfirmFlags adds the --yes and --dry-run flags used by confirm, where action is what is confirmed
+func addConfirmFlags(cmd *cobra.Command, action string) {
+	cmd.Flags().BoolP(flag.Yes, "y", false, action+" without asking for confirmation")
+	cmd.Flags().Bool(flag.DryRun, false, "only show what would be "+past(action))
+}
+
+// addDeleteFlags adds the --yes and --dry-run flags used by confirmDelete
+func addDeleteFlags(cmd *cobra.Command) {
+	addConfirmFlags(cmd, "delete")
+}
+
+// confirm shows the items the action is done to and asks the user to confirm it, and returns false if the
+// user declined or --dry-run was used. When stdin isn't a terminal it refuses to proceed without --yes.
+func confirm(cmd *cobra.Command, action string, items []string) (bool, error) {
+	out := cmd.OutOrStdout()
+
+	if dryRun, _ := cmd.Flags().GetBool(flag.DryRun); dryRun {
+		_, _ = fmt.Fprintf(out, "would %s:\n", action)
+		for _, item := range items {
+			_, _ = fmt.Fprintf(out, "  %s\n", item)
+		}
+		return false, nil
+	}
+
+	if yes, _ := cmd.Flags().GetBool(flag.Yes); yes {
+		return true, nil
+	}
+
+	in := cmd.InOrStdin()
+	if !isTerminal(in) {
+		return false, fmt.Errorf("refusing to %s without --%s when stdin isn't a terminal", action, flag.Yes)
+	}
+
+	c := tui.NewConfirm(fmt.Sprintf("%s %s?", action, plural(len(items), "resource")), items)
+	if _, err := tea.NewProgram(c, tea.WithInput(in), tea.WithOutput(out), tea.WithContext(cmd.Context())).Run(); err != nil {
+		return false, fmt.Errorf("failed to ask for confirmation: %w", err)
+	}
+
+	return c.Confirmed, nil
+}
+
+// confirmDelete shows what will be deleted and asks the user to confirm it, see confirm
+func confirmDelete(cmd *cobra.Command, items []string) (bool, error) {
+	return confirm(cmd, "delete", items)
+}
+
+// past returns the past tense of the action
+func past(action string) string {
+	if strings.HasSuffix(action, "e") {
+		return action + "d"
+	}
+
+	return action + "ed"
+}
+
+// isTerminal returns true if the reader or writer is a terminal
+func isTerminal(v any) bool {
+	if f, ok := v.(*os.File); ok {
+		return term.IsTerminal(int(f.Fd()))
+	}
+
+	return false
+}
+
+// plural returns the count and the noun, with an s if the count isn't one
+func plural(count int, noun string) string {
+	if count == 1 {
+		return "1 " + noun
+	}
+
+	return fmt.Sprintf("%d %ss", count, noun)
+}
@@ -0,0 +1,509 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/rockset/rockset-go-client"
+	rockerr "github.com/rockset/rockset-go-client/errors"
+	"github.com/rockset/rockset-go-client/openapi"
+	"github.com/rockset/rockset-go-client/option"
+	"github.com/spf13/cobra"
+
+	"github.com/rockset/cli/completion"
+	"github.com/rockset/cli/config"
+	"github.com/rockset/cli/flag"
+	"github.com/rockset/cli/graph"
+)
+
+// latestTag is maintained by Rockset, so it isn't copied
+const latestTag = "latest"
+
+func newCopyWorkspaceCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:     "workspace SRC",
+		Aliases: []string{"ws"},
+		Short:   "copy workspace",
+		Long: `copy the collections, aliases, views and query lambdas of a workspace to another workspace,
+optionally in another configuration context, e.g. a different organization.
+
+Collections are created with the same sources, ingest transformation and retention, but the data isn't copied,
+it is ingested again from the sources, so the integrations they use must exist in the target organization.
+Collections with file upload sources are created empty. Query lambdas are copied with all their versions and tags.
+
+The resources are created in dependency order, and references to the source workspace in the SQL of views and
+query lambdas, and in the collections of aliases, are rewritten to the target workspace. Resources which already
+exist in the target workspace are skipped, except for query lambdas which get the versions and tags they are
+missing, so the command can be run again if it failed part of the way.`,
+		Example: `	## copy the commons workspace to the prod context
+	rockset copy workspace commons --to-context prod
+
+	## show what would be created when copying the staging workspace to the live workspace
+	rockset copy workspace staging --as live --dry-run`,
+		Args:              cobra.ExactArgs(1),
+		Annotations:       group("workspace"),
+		ValidArgsFunction: completion.Workspace(Version),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			from := args[0]
+			to, _ := cmd.Flags().GetString(flag.As)
+			toContext, _ := cmd.Flags().GetString(flag.ToContext)
+			dryRun, _ := cmd.Flags().GetBool(flag.DryRun)
+			out := cmd.OutOrStdout()
+
+			if to == "" {
+				to = from
+			}
+
+			srcContext, err := config.ContextName(cmd)
+			if err != nil {
+				return err
+			}
+			if toContext == "" {
+				toContext = srcContext
+			}
+			if toContext == srcContext && to == from {
+				return fmt.Errorf("can't copy workspace %s to itself, use --%s or --%s", from, flag.As, flag.ToContext)
+			}
+
+			src, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			dst, err := config.ContextClient(toContext, Version)
+			if err != nil {
+				return err
+			}
+
+			steps, err := copyWorkspacePlan(ctx, src, dst, from, to)
+			if err != nil {
+				return err
+			}
+
+			for _, s := range steps {
+				switch {
+				case s.skip != "":
+					_, _ = fmt.Fprintf(out, "skipping %s: %s\n", s.resource, s.skip)
+				case dryRun:
+					_, _ = fmt.Fprintf(out, "would create %s\n", s.resource)
+				default:
+					if err = s.run(ctx); err != nil {
+						return fmt.Errorf("failed to create %s: %w", s.resource, err)
+					}
+					_, _ = fmt.Fprintf(out, "created %s\n", s.resource)
+				}
+			}
+
+			if !dryRun {
+				_, _ = fmt.Fprintf(out, "workspace %s copied to %s in %s\n", from, to, toContext)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flag.ToContext, "", "configuration context to copy the workspace to, defaults to the current")
+	cmd.Flags().String(flag.As, "", "name of the target workspace, defaults to the source workspace")
+	cmd.Flags().Bool(flag.DryRun, false, "only show what would be created")
+
+	return &cmd
+}
+
+// copyStep creates a single resource in the target workspace
+type copyStep struct {
+	resource string
+	// skip is why the step is skipped, e.g. because the resource already exists
+	skip string
+	run  func(ctx context.Context) error
+}
+
+// copyWorkspacePlan returns the steps to copy the workspace, in the order they have to be run
+func copyWorkspacePlan(ctx context.Context, src, dst *rockset.RockClient, from, to string) ([]copyStep, error) {
+	collections, err := src.ListCollections(ctx, option.WithWorkspace(from))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+	aliases, err := src.ListAliases(ctx, option.WithAliasWorkspace(from))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list aliases: %w", err)
+	}
+	views, err := src.ListViews(ctx, option.WithViewWorkspace(from))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list views: %w", err)
+	}
+	lambdas, err := src.ListQueryLambdas(ctx, option.WithQueryLambdaWorkspace(from))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list query lambdas: %w", err)
+	}
+
+	existing, err := existingResources(ctx, dst, to)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkIntegrations(ctx, dst, collections); err != nil {
+		return nil, err
+	}
+
+	exists := func(kind graph.Kind, name string) string {
+		if existing.names[kind][name] {
+			return "already exists"
+		}
+		return ""
+	}
+
+	var steps []copyStep
+
+	ws := copyStep{
+		resource: "workspace " + to,
+		run: func(ctx context.Context) error {
+			_, err := dst.CreateWorkspace(ctx, to)
+			return err
+		},
+	}
+	if existing.workspace {
+		ws.skip = "already exists"
+	}
+	steps = append(steps, ws)
+
+	for _, c := range collections {
+		c := c
+		steps = append(steps, copyStep{
+			resource: fmt.Sprintf("collection %s.%s", to, c.GetName()),
+			skip:     exists(graph.Collection, c.GetName()),
+			run: func(ctx context.Context) error {
+				_, err := dst.CreateCollection(ctx, to, c.GetName(), withCollectionCopy(c))
+				return err
+			},
+		})
+	}
+
+	for _, a := range aliases {
+		a := a
+		steps = append(steps, copyStep{
+			resource: fmt.Sprintf("alias %s.%s", to, a.GetName()),
+			skip:     exists(graph.Alias, a.GetName()),
+			run: func(ctx context.Context) error {
+				collections := make([]string, len(a.Collections))
+				for i, c := range a.Collections {
+					collections[i] = RewriteWorkspace(c, from, to)
+				}
+				_, err := dst.CreateAlias(ctx, to, a.GetName(), collections,
+					option.WithAliasDescription(a.GetDescription()))
+				return err
+			},
+		})
+	}
+
+	for _, v := range viewOrder(views, from) {
+		v := v
+		steps = append(steps, copyStep{
+			resource: fmt.Sprintf("view %s.%s", to, v.GetName()),
+			skip:     exists(graph.View, v.GetName()),
+			run: func(ctx context.Context) error {
+				_, err := dst.CreateView(ctx, to, v.GetName(), RewriteWorkspace(v.GetQuerySql(), from, to),
+					option.WithViewDescription(v.GetDescription()))
+				return err
+			},
+		})
+	}
+
+	for _, ql := range lambdas {
+		name := ql.GetName()
+		found := existing.names[graph.Lambda][name]
+		resource := fmt.Sprintf("query lambda %s.%s", to, name)
+		if found {
+			// a query lambda is created with its first version, so copying the rest of them might have failed
+			resource = "missing versions and tags of " + resource
+		}
+		steps = append(steps, copyStep{
+			resource: resource,
+			run: func(ctx context.Context) error {
+				return copyQueryLambda(ctx, src, dst, from, to, name, found)
+			},
+		})
+	}
+
+	return steps, nil
+}
+
+// targetResources are the resources which already exist in the target workspace
+type targetResources struct {
+	workspace bool
+	names     map[graph.Kind]map[string]bool
+}
+
+// existingResources returns the resources in the target workspace, which has no resources if it doesn't exist
+func existingResources(ctx context.Context, rs *rockset.RockClient, ws string) (targetResources, error) {
+	existing := targetResources{names: make(map[graph.Kind]map[string]bool)}
+
+	if _, err := rs.GetWorkspace(ctx, ws); err != nil {
+		var re rockerr.Error
+		if errors.As(err, &re) && re.IsNotFoundError() {
+			return existing, nil
+		}
+		return existing, fmt.Errorf("failed to get target workspace: %w", err)
+	}
+	existing.workspace = true
+
+	add := func(kind graph.Kind, name string) {
+		if existing.names[kind] == nil {
+			existing.names[kind] = make(map[string]bool)
+		}
+		existing.names[kind][name] = true
+	}
+
+	collections, err := rs.ListCollections(ctx, option.WithWorkspace(ws))
+	if err != nil {
+		return existing, fmt.Errorf("failed to list target collections: %w", err)
+	}
+	for _, c := range collections {
+		add(graph.Collection, c.GetName())
+	}
+
+	aliases, err := rs.ListAliases(ctx, option.WithAliasWorkspace(ws))
+	if err != nil {
+		return existing, fmt.Errorf("failed to list target aliases: %w", err)
+	}
+	for _, a := range aliases {
+		add(graph.Alias, a.GetName())
+	}
+
+	views, err := rs.ListViews(ctx, option.WithViewWorkspace(ws))
+	if err != nil {
+		return existing, fmt.Errorf("failed to list target views: %w", err)
+	}
+	for _, v := range views {
+		add(graph.View, v.GetName())
+	}
+
+	lambdas, err := rs.ListQueryLambdas(ctx, option.WithQueryLambdaWorkspace(ws))
+	if err != nil {
+		return existing, fmt.Errorf("failed to list target query lambdas: %w", err)
+	}
+	for _, ql := range lambdas {
+		add(graph.Lambda, ql.GetName())
+	}
+
+	return existing, nil
+}
+
+// checkIntegrations verifies that the integrations used by the collection sources exist in the target
+func checkIntegrations(ctx context.Context, rs *rockset.RockClient, collections []openapi.Collection) error {
+	integrations, err := rs.ListIntegrations(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list target integrations: %w", err)
+	}
+
+	var names []string
+	for _, i := range integrations {
+		names = append(names, i.GetName())
+	}
+
+	var missing []string
+	for _, c := range collections {
+		for _, s := range c.Sources {
+			if n := s.GetIntegrationName(); n != "" && !slices.Contains(names, n) && !slices.Contains(missing, n) {
+				missing = append(missing, n)
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("the target is missing integrations used by the collections: %s",
+			strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// withCollectionCopy creates the collection with the same definition as c
+func withCollectionCopy(c openapi.Collection) option.CollectionOption {
+	return func(o *openapi.CreateCollectionRequest) {
+		o.Description = c.Description
+		o.ClusteringKey = c.ClusteringKey
+		o.FieldMappingQuery = c.FieldMappingQuery
+		o.RetentionSecs = c.RetentionSecs
+		o.StorageCompressionType = c.StorageCompressionType
+
+		for _, s := range c.Sources {
+			// uploaded files can't be ingested again
+			if s.FileUpload != nil || s.System != nil {
+				continue
+			}
+
+			// clear what Rockset sets about the source in the source collection
+			s.Id = nil
+			s.Status = nil
+			s.ResumeAt = nil
+			s.SuspendedAt = nil
+			if s.Kafka != nil {
+				kafka := *s.Kafka
+				kafka.Status = nil
+				s.Kafka = &kafka
+			}
+			o.Sources = append(o.Sources, s)
+		}
+	}
+}
+
+// copyQueryLambda copies all versions of the query lambda, oldest first, and then moves the tags to the
+// copies of the versions they point to. If the query lambda exists in the target, only the versions and tags
+// it is missing are copied.
+func copyQueryLambda(ctx context.Context, src, dst *rockset.RockClient, from, to, name string, exists bool) error {
+	versions, err := src.ListQueryLambdaVersions(ctx, from, name)
+	if err != nil {
+		return err
+	}
+
+	var copied []openapi.QueryLambdaVersion
+	if exists {
+		if copied, err = dst.ListQueryLambdaVersions(ctx, to, name); err != nil {
+			return err
+		}
+	}
+
+	copies, missing, err := QueryLambdaCopies(versions, copied, from, to)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range missing {
+		var options []option.CreateQueryLambdaOption
+		if d := v.GetDescription(); d != "" {
+			options = append(options, option.WithQueryLambdaDescription(d))
+		}
+		sql := v.GetSql()
+		for _, p := range sql.DefaultParameters {
+			options = append(options, option.WithDefaultParameter(p.GetName(), p.GetType(), p.GetValue()))
+		}
+		query := RewriteWorkspace(sql.GetQuery(), from, to)
+
+		var created openapi.QueryLambdaVersion
+		if len(copies) == 0 {
+			created, err = dst.CreateQueryLambda(ctx, to, name, query, options...)
+		} else {
+			created, err = dst.UpdateQueryLambda(ctx, to, name, query, options...)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to copy version %s: %w", v.GetVersion(), err)
+		}
+		copies[v.GetVersion()] = created.GetVersion()
+	}
+
+	tags, err := src.ListQueryLambdaTags(ctx, from, name)
+	if err != nil {
+		return err
+	}
+	var copiedTags []openapi.QueryLambdaTag
+	if exists {
+		if copiedTags, err = dst.ListQueryLambdaTags(ctx, to, name); err != nil {
+			return err
+		}
+	}
+	for _, t := range tags {
+		version, found := copies[t.Version.GetVersion()]
+		if t.GetTagName() == latestTag || !found || hasTag(copiedTags, t.GetTagName(), version) {
+			continue
+		}
+		if _, err = dst.CreateQueryLambdaTag(ctx, to, name, version, t.GetTagName()); err != nil {
+			return fmt.Errorf("failed to copy tag %s: %w", t.GetTagName(), err)
+		}
+	}
+
+	return nil
+}
+
+// QueryLambdaCopies matches the versions already copied to the target with the source versions, and returns
+// the copies of the source versions, and the versions which are missing in the target, oldest first. As the
+// versions are copied oldest first, the versions in the target must be copies of the oldest source versions.
+func QueryLambdaCopies(versions, copied []openapi.QueryLambdaVersion,
+	from, to string) (map[string]string, []openapi.QueryLambdaVersion, error) {
+	versions, copied = slices.Clone(versions), slices.Clone(copied)
+	for _, list := range [][]openapi.QueryLambdaVersion{versions, copied} {
+		sort.SliceStable(list, func(i, j int) bool {
+			return parseISO8601Millis(list[i].GetCreatedAt()) < parseISO8601Millis(list[j].GetCreatedAt())
+		})
+	}
+
+	if len(copied) > len(versions) {
+		return nil, nil, fmt.Errorf("the target query lambda has %d versions, but the source only has %d",
+			len(copied), len(versions))
+	}
+
+	copies := make(map[string]string, len(versions))
+	for i, c := range copied {
+		v := versions[i]
+		sql, copiedSQL := v.GetSql(), c.GetSql()
+		if RewriteWorkspace(sql.GetQuery(), from, to) != copiedSQL.GetQuery() {
+			return nil, nil, fmt.Errorf("version %s of the target query lambda isn't a copy of version %s",
+				c.GetVersion(), v.GetVersion())
+		}
+		copies[v.GetVersion()] = c.GetVersion()
+	}
+
+	return copies, versions[len(copied):], nil
+}
+
+// hasTag returns true if the tag points to the version
+func hasTag(tags []openapi.QueryLambdaTag, tag, version string) bool {
+	return slices.ContainsFunc(tags, func(t openapi.QueryLambdaTag) bool {
+		return t.GetTagName() == tag && t.Version.GetVersion() == version
+	})
+}
+
+// viewOrder sorts the views so a view comes after the views in the same workspace it selects from
+func viewOrder(views []openapi.View, ws string) []openapi.View {
+	byName := make(map[string]openapi.View, len(views))
+	for _, v := range views {
+		byName[v.GetName()] = v
+	}
+
+	var ordered []openapi.View
+	added := make(map[string]bool, len(views))
+	var add func(v openapi.View)
+	add = func(v openapi.View) {
+		if added[v.GetName()] {
+			return
+		}
+		added[v.GetName()] = true
+
+		refs := v.Entities
+		if len(refs) == 0 {
+			refs = graph.SQLReferences(v.GetQuerySql())
+		}
+		for _, r := range refs {
+			refWs, refName, _ := strings.Cut(r, ".")
+			if dep, found := byName[refName]; found && refWs == ws {
+				add(dep)
+			}
+		}
+		ordered = append(ordered, v)
+	}
+
+	for _, v := range views {
+		add(v)
+	}
+
+	return ordered
+}
+
+// RewriteWorkspace replaces references to the workspace from with the workspace to, where the
+// workspace is followed by a dot, and might be quoted
+func RewriteWorkspace(sql, from, to string) string {
+	re := regexp.MustCompile(`(^|[^\w.])("?)` + regexp.QuoteMeta(from) + `("?\s*\.)`)
+	return re.ReplaceAllStringFunc(sql, func(m string) string {
+		sub := re.FindStringSubmatch(m)
+		// a quote must be matched by a closing quote
+		if (sub[2] == `"`) != strings.HasPrefix(sub[3], `"`) {
+			return m
+		}
+		return sub[1] + sub[2] + to + sub[3]
+	})
+}
@@ -0,0 +1,82 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/rockset/rockset-go-client/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/cli/cmd"
+)
+
+func TestRewriteWorkspace(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want string
+	}{
+		{"path", "commons.movies", "live.movies"},
+		{"from", "SELECT * FROM commons.movies m JOIN commons.ratings r ON m.id = r.id",
+			"SELECT * FROM live.movies m JOIN live.ratings r ON m.id = r.id"},
+		{"quoted", `SELECT * FROM "commons"."movies"`, `SELECT * FROM "live"."movies"`},
+		{"space", "SELECT * FROM commons . movies", "SELECT * FROM live . movies"},
+		{"other workspace", "SELECT * FROM commons2.movies, xcommons.movies", "SELECT * FROM commons2.movies, xcommons.movies"},
+		{"field", "SELECT m.commons.title FROM commons.movies m", "SELECT m.commons.title FROM live.movies m"},
+		{"unbalanced quote", `SELECT * FROM "commons.movies"`, `SELECT * FROM "commons.movies"`},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			assert.Equal(t, tst.want, cmd.RewriteWorkspace(tst.sql, "commons", "live"))
+		})
+	}
+}
+
+func TestQueryLambdaCopies(t *testing.T) {
+	version := func(v, createdAt, query string) openapi.QueryLambdaVersion {
+		return openapi.QueryLambdaVersion{
+			Version:   &v,
+			CreatedAt: &createdAt,
+			Sql:       &openapi.QueryLambdaSql{Query: query},
+		}
+	}
+	// listed newest first, as the API does
+	versions := []openapi.QueryLambdaVersion{
+		version("c", "2024-01-03T00:00:00Z", "SELECT 3 FROM commons.movies"),
+		version("b", "2024-01-02T00:00:00Z", "SELECT 2 FROM commons.movies"),
+		version("a", "2024-01-01T00:00:00Z", "SELECT 1 FROM commons.movies"),
+	}
+
+	t.Run("new", func(t *testing.T) {
+		copies, missing, err := cmd.QueryLambdaCopies(versions, nil, "commons", "live")
+		require.NoError(t, err)
+		assert.Empty(t, copies)
+		require.Len(t, missing, 3)
+		assert.Equal(t, "a", missing[0].GetVersion())
+		assert.Equal(t, "c", missing[2].GetVersion())
+	})
+
+	t.Run("partial", func(t *testing.T) {
+		copied := []openapi.QueryLambdaVersion{
+			version("y", "2024-02-02T00:00:00Z", "SELECT 2 FROM live.movies"),
+			version("x", "2024-02-01T00:00:00Z", "SELECT 1 FROM live.movies"),
+		}
+		copies, missing, err := cmd.QueryLambdaCopies(versions, copied, "commons", "live")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"a": "x", "b": "y"}, copies)
+		require.Len(t, missing, 1)
+		assert.Equal(t, "c", missing[0].GetVersion())
+	})
+
+	t.Run("not a copy", func(t *testing.T) {
+		copied := []openapi.QueryLambdaVersion{version("x", "2024-02-01T00:00:00Z", "SELECT 42")}
+		_, _, err := cmd.QueryLambdaCopies(versions, copied, "commons", "live")
+		assert.Error(t, err)
+	})
+
+	t.Run("more versions", func(t *testing.T) {
+		_, _, err := cmd.QueryLambdaCopies(versions[:1], versions, "commons", "commons")
+		assert.Error(t, err)
+	})
+}
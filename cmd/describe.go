@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	gosort "sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rockset/cli/completion"
+	"github.com/rockset/cli/config"
+	"github.com/rockset/cli/flag"
+	"github.com/rockset/cli/format"
+	"github.com/rockset/cli/tui"
+)
+
+func newDescribeCollectionCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:     "collection [WORKSPACE.]NAME",
+		Aliases: []string{"coll", "c"},
+		Short:   "describe the fields of a collection",
+		Long: `describe the fields of a collection using DESCRIBE, and show them as a tree with their types,
+and how often each type occurs in the documents.
+
+Use --format json to get the tree as JSON.`,
+		Example: `	## show the fields two levels deep
+	rockset describe collection commons.movies --depth 2
+
+	## describe a 10% sample of a large collection
+	rockset describe collection commons.movies --sample 0.1`,
+		Annotations:       group("collection"),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.Collection(Version),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			depth, _ := cmd.Flags().GetInt(flag.Depth)
+			sample, _ := cmd.Flags().GetFloat64(flag.Sample)
+			ws, name := collectionPath(cmd, args[0])
+
+			if sample < 0 || sample > 1 {
+				return fmt.Errorf("--%s must be between 0 and 1", flag.Sample)
+			}
+
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			resp, err := rs.Query(ctx, DescribeSQL(ws, name, depth, sample))
+			if err != nil {
+				return err
+			}
+			if resp.GetStatus() == "ERROR" {
+				return queryResponseError(resp)
+			}
+
+			fields, err := BuildFieldTree(resp.Results)
+			if err != nil {
+				return err
+			}
+
+			if f, _ := cmd.Flags().GetString(flag.Format); f == string(format.JSONFormat) {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(fields)
+			}
+
+			showFieldTree(cmd.OutOrStdout(), fields)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP(flag.Workspace, flag.WorkspaceShort, flag.DefaultWorkspace, "workspace for the collection")
+	_ = cmd.RegisterFlagCompletionFunc(flag.Workspace, completion.Workspace(Version))
+	cmd.Flags().Int(flag.Depth, 0, "maximum field depth to describe, 0 means no limit")
+	cmd.Flags().Float64(flag.Sample, 0, "ratio of the documents to sample, between 0 and 1, 0 means all documents")
+
+	return &cmd
+}
+
+// collectionPath returns the workspace and name of a collection given either as WORKSPACE.NAME,
+// or as NAME with the workspace from the --workspace flag
+func collectionPath(cmd *cobra.Command, arg string) (string, string) {
+	ws, _ := cmd.Flags().GetString(flag.Workspace)
+	if cmd.Flags().Changed(flag.Workspace) {
+		return ws, arg
+	}
+
+	if w, name, found := strings.Cut(arg, "."); found {
+		return w, name
+	}
+
+	return ws, arg
+}
+
+// DescribeSQL returns the DESCRIBE statement for the collection, with quoted identifiers
+func DescribeSQL(ws, name string, depth int, sample float64) string {
+	var options []string
+	if depth > 0 {
+		options = append(options, fmt.Sprintf("max_field_depth=%d", depth))
+	}
+	if sample > 0 {
+		options = append(options, fmt.Sprintf("sample_ratio=%g", sample))
+	}
+
+	sql := fmt.Sprintf("DESCRIBE %s.%s", quoteIdentifier(ws), quoteIdentifier(name))
+	if len(options) > 0 {
+		sql += fmt.Sprintf(" OPTION(%s)", strings.Join(options, ", "))
+	}
+
+	return sql
+}
+
+// quoteIdentifier quotes a SQL identifier, so it can contain any character or be a reserved word
+func quoteIdentifier(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+// CollectionField is a field in a collection, with the types it has and the nested fields
+type CollectionField struct {
+	Name   string             `json:"name"`
+	Path   []string           `json:"path"`
+	Types  []FieldType        `json:"types"`
+	Fields []*CollectionField `json:"fields,omitempty"`
+}
+
+// FieldType is how often a type occurs for a field
+type FieldType struct {
+	Type        string  `json:"type"`
+	Occurrences int64   `json:"occurrences"`
+	Total       int64   `json:"total"`
+	Percent     float64 `json:"percent"`
+}
+
+// BuildFieldTree turns the rows returned by DESCRIBE, which have the field path, type, occurrences
+// and total, into a tree of fields
+func BuildFieldTree(rows []map[string]any) ([]*CollectionField, error) {
+	root := &CollectionField{}
+
+	for i, row := range rows {
+		raw, ok := row["field"].([]any)
+		if !ok || len(raw) == 0 {
+			return nil, fmt.Errorf("row %d has no field path", i)
+		}
+
+		path := make([]string, len(raw))
+		for j, p := range raw {
+			path[j] = fmt.Sprintf("%v", p)
+		}
+
+		typ, _ := row["type"].(string)
+		occurrences, _ := row["occurrences"].(float64)
+		total, _ := row["total"].(float64)
+
+		ft := FieldType{
+			Type:        typ,
+			Occurrences: int64(occurrences),
+			Total:       int64(total),
+		}
+		if total > 0 {
+			ft.Percent = 100 * occurrences / total
+		}
+
+		node := root
+		for j := range path {
+			node = node.child(path[:j+1])
+		}
+		node.Types = append(node.Types, ft)
+	}
+
+	root.sort()
+
+	return root.Fields, nil
+}
+
+func (f *CollectionField) child(path []string) *CollectionField {
+	name := path[len(path)-1]
+	for _, c := range f.Fields {
+		if c.Name == name {
+			return c
+		}
+	}
+
+	c := &CollectionField{Name: name, Path: append([]string{}, path...)}
+	f.Fields = append(f.Fields, c)
+
+	return c
+}
+
+func (f *CollectionField) sort() {
+	gosort.Slice(f.Fields, func(i, j int) bool {
+		return f.Fields[i].Name < f.Fields[j].Name
+	})
+	gosort.SliceStable(f.Types, func(i, j int) bool {
+		return f.Types[i].Occurrences > f.Types[j].Occurrences
+	})
+
+	for _, c := range f.Fields {
+		c.sort()
+	}
+}
+
+func showFieldTree(out io.Writer, fields []*CollectionField) {
+	t := tui.NewTable(out)
+	t.Headers("field", "type", "occurrences")
+
+	// top level fields are shown without a branch, and the prefix is what comes before the branch
+	var add func(fields []*CollectionField, prefix string, top bool)
+	add = func(fields []*CollectionField, prefix string, top bool) {
+		for i, f := range fields {
+			branch, indent := "├─ ", "│  "
+			if i == len(fields)-1 {
+				branch, indent = "└─ ", "   "
+			}
+			if top {
+				branch, indent = "", ""
+			}
+
+			types := make([]string, len(f.Types))
+			percents := make([]string, len(f.Types))
+			for j, ft := range f.Types {
+				types[j] = ft.Type
+				percents[j] = fmt.Sprintf("%.1f%%", ft.Percent)
+			}
+
+			t.Row(prefix+branch+f.Name, strings.Join(types, ", "), strings.Join(percents, ", "))
+			add(f.Fields, prefix+indent, false)
+		}
+	}
+	add(fields, "", true)
+
+	_, _ = fmt.Fprintln(out, t.Render())
+}
@@ -0,0 +1,50 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/cli/cmd"
+)
+
+func TestBuildFieldTree(t *testing.T) {
+	rows := []map[string]any{
+		{"field": []any{"_id"}, "type": "string", "occurrences": float64(10), "total": float64(10)},
+		{"field": []any{"address"}, "type": "object", "occurrences": float64(8), "total": float64(10)},
+		{"field": []any{"address", "zip"}, "type": "int", "occurrences": float64(2), "total": float64(8)},
+		{"field": []any{"address", "zip"}, "type": "string", "occurrences": float64(6), "total": float64(8)},
+		{"field": []any{"address", "city"}, "type": "string", "occurrences": float64(8), "total": float64(8)},
+	}
+
+	fields, err := cmd.BuildFieldTree(rows)
+	require.NoError(t, err)
+	require.Len(t, fields, 2)
+
+	assert.Equal(t, "_id", fields[0].Name)
+	assert.Equal(t, 100.0, fields[0].Types[0].Percent)
+
+	address := fields[1]
+	assert.Equal(t, 80.0, address.Types[0].Percent)
+	require.Len(t, address.Fields, 2)
+	assert.Equal(t, "city", address.Fields[0].Name)
+
+	zip := address.Fields[1]
+	assert.Equal(t, []string{"address", "zip"}, zip.Path)
+	require.Len(t, zip.Types, 2)
+	// the most common type comes first
+	assert.Equal(t, "string", zip.Types[0].Type)
+	assert.Equal(t, 75.0, zip.Types[0].Percent)
+
+	_, err = cmd.BuildFieldTree([]map[string]any{{"type": "string"}})
+	assert.Error(t, err)
+}
+
+func TestDescribeSQL(t *testing.T) {
+	assert.Equal(t, `DESCRIBE "commons"."movies"`, cmd.DescribeSQL("commons", "movies", 0, 0))
+	assert.Equal(t, `DESCRIBE "my-ws"."select"`, cmd.DescribeSQL("my-ws", "select", 0, 0))
+	assert.Equal(t, `DESCRIBE "commons"."a""b"`, cmd.DescribeSQL("commons", `a"b`, 0, 0))
+	assert.Equal(t, `DESCRIBE "commons"."movies" OPTION(max_field_depth=2, sample_ratio=0.1)`,
+		cmd.DescribeSQL("commons", "movies", 2, 0.1))
+}
@@ -28,6 +28,14 @@ func newDeleteDocumentsCmd() *cobra.Command {
 				return err
 			}
 
+			items := make([]string, len(args))
+			for i, id := range args {
+				items[i] = fmt.Sprintf("document %s in %s.%s", id, ws, coll)
+			}
+			if ok, err := confirmDelete(cmd, items); err != nil || !ok {
+				return err
+			}
+
 			// TODO make it possible to read document IDs from stdin
 			res, err := rs.DeleteDocuments(ctx, ws, coll, args)
 			if err != nil {
@@ -59,6 +67,7 @@ func newDeleteDocumentsCmd() *cobra.Command {
 	cmd.Flags().String(flag.Collection, "", "collection name")
 	_ = cmd.MarkFlagRequired(flag.Collection)
 	_ = cmd.RegisterFlagCompletionFunc(flag.Collection, completion.Collection(Version))
+	addDeleteFlags(&cmd)
 
 	return &cmd
 }
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rockset/rockset-go-client"
+	"github.com/rockset/rockset-go-client/option"
+	"github.com/spf13/cobra"
+
+	"github.com/rockset/cli/completion"
+	"github.com/rockset/cli/config"
+	"github.com/rockset/cli/flag"
+	"github.com/rockset/cli/graph"
+)
+
+// graph output formats
+const (
+	graphDOT     = "dot"
+	graphMermaid = "mermaid"
+	graphText    = "text"
+)
+
+var graphFormats = []string{graphText, graphDOT, graphMermaid}
+
+func newGraphCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "graph",
+		Short: "show resource dependencies",
+		Long: `show the dependencies between collections, aliases, views and query lambdas.
+
+Views depend on what their SQL selects from, aliases on the collections they point to, and query lambdas on the
+collections referenced by their latest version. The text output shows each resource and what uses it,
+while the DOT and Mermaid output have edges pointing from a resource to what it depends on.`,
+		Example: `	## render the dependencies of the commons workspace with Graphviz
+	rockset graph -W commons --graph-format dot | dot -Tsvg > commons.svg
+
+	## show the dependencies as a Mermaid flowchart
+	rockset graph --graph-format mermaid`,
+		Args:        cobra.NoArgs,
+		Annotations: group("workspace"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			ws, _ := cmd.Flags().GetString(flag.Workspace)
+			f, _ := cmd.Flags().GetString(flag.GraphFormat)
+
+			if !slices.Contains(graphFormats, f) {
+				return fmt.Errorf("unknown graph format %s, must be one of: %s", f, strings.Join(graphFormats, ", "))
+			}
+
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			g, err := buildGraph(ctx, rs, ws)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			switch f {
+			case graphDOT:
+				return g.DOT(out)
+			case graphMermaid:
+				return g.Mermaid(out)
+			default:
+				return g.Text(out)
+			}
+		},
+	}
+
+	cmd.Flags().StringP(flag.Workspace, flag.WorkspaceShort, flag.AllWorkspaces, "only include resources in the workspace")
+	_ = cmd.RegisterFlagCompletionFunc(flag.Workspace, completion.Workspace(Version))
+	cmd.Flags().String(flag.GraphFormat, graphText, "graph format, one of: "+strings.Join(graphFormats, ", "))
+	_ = cmd.RegisterFlagCompletionFunc(flag.GraphFormat,
+		cobra.FixedCompletions(graphFormats, cobra.ShellCompDirectiveNoFileComp))
+
+	return &cmd
+}
+
+// buildGraph builds the dependency graph of the resources in the workspace, or all workspaces
+func buildGraph(ctx context.Context, rs *rockset.RockClient, ws string) (*graph.Graph, error) {
+	var collectionOpts []option.ListCollectionOption
+	var aliasOpts []option.ListAliasesOption
+	var viewOpts []option.ListViewOption
+	var lambdaOpts []option.ListQueryLambdaOption
+	if ws != "" && ws != flag.AllWorkspaces {
+		collectionOpts = append(collectionOpts, option.WithWorkspace(ws))
+		aliasOpts = append(aliasOpts, option.WithAliasWorkspace(ws))
+		viewOpts = append(viewOpts, option.WithViewWorkspace(ws))
+		lambdaOpts = append(lambdaOpts, option.WithQueryLambdaWorkspace(ws))
+	}
+
+	g := graph.New()
+
+	collections, err := rs.ListCollections(ctx, collectionOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+	for _, c := range collections {
+		g.Add(graph.Node{Kind: graph.Collection, Workspace: c.GetWorkspace(), Name: c.GetName()})
+	}
+
+	aliases, err := rs.ListAliases(ctx, aliasOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list aliases: %w", err)
+	}
+	for _, a := range aliases {
+		n := graph.Node{Kind: graph.Alias, Workspace: a.GetWorkspace(), Name: a.GetName()}
+		g.Add(n)
+		for _, c := range a.Collections {
+			g.DependsOn(n, c)
+		}
+	}
+
+	views, err := rs.ListViews(ctx, viewOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list views: %w", err)
+	}
+	for _, v := range views {
+		n := graph.Node{Kind: graph.View, Workspace: v.GetWorkspace(), Name: v.GetName()}
+		g.Add(n)
+
+		// prefer the entities resolved by Rockset, and only parse the SQL if they are missing
+		refs := v.Entities
+		if len(refs) == 0 {
+			refs = graph.SQLReferences(v.GetQuerySql())
+		}
+		for _, r := range refs {
+			g.DependsOn(n, r)
+		}
+	}
+
+	lambdas, err := rs.ListQueryLambdas(ctx, lambdaOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list query lambdas: %w", err)
+	}
+	for _, ql := range lambdas {
+		n := graph.Node{Kind: graph.Lambda, Workspace: ql.GetWorkspace(), Name: ql.GetName()}
+		g.Add(n)
+
+		refs := slices.Clone(ql.Collections)
+		if ql.LatestVersion != nil {
+			refs = append(refs, ql.LatestVersion.Collections...)
+		}
+		for _, r := range refs {
+			g.DependsOn(n, r)
+		}
+	}
+
+	return g, nil
+}
+
+// warnBrokenDependents warns about resources which will break when the removed resources are deleted,
+// and failing to build the graph is logged as it shouldn't prevent the delete
+func warnBrokenDependents(ctx context.Context, cmd *cobra.Command, rs *rockset.RockClient, removed ...graph.Node) {
+	g, err := buildGraph(ctx, rs, flag.AllWorkspaces)
+	if err != nil {
+		logger.Warn("failed to check dependencies", "err", err)
+		return
+	}
+
+	printBroken(cmd, g.Broken(removed))
+}
+
+// warnBrokenWorkspaceDependents warns about resources in other workspaces which will break when the workspace
+// and everything in it is deleted
+func warnBrokenWorkspaceDependents(ctx context.Context, cmd *cobra.Command, rs *rockset.RockClient, ws string) {
+	g, err := buildGraph(ctx, rs, flag.AllWorkspaces)
+	if err != nil {
+		logger.Warn("failed to check dependencies", "err", err)
+		return
+	}
+
+	printBroken(cmd, g.Broken(g.InWorkspace(ws)))
+}
+
+func printBroken(cmd *cobra.Command, broken []graph.Node) {
+	if len(broken) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "warning: these resources depend on what is deleted, and will break:\n")
+	for _, n := range broken {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "  %s\n", n)
+	}
+}
@@ -4,11 +4,9 @@ import (
 	"fmt"
 
 	"github.com/rockset/rockset-go-client/openapi"
-	"github.com/rockset/rockset-go-client/option"
 	"github.com/spf13/cobra"
 
 	"github.com/rockset/cli/config"
-	"github.com/rockset/cli/flag"
 	"github.com/rockset/cli/format"
 	"github.com/rockset/cli/sort"
 )
@@ -69,38 +67,6 @@ func newListIntegrationsCmd() *cobra.Command {
 	}
 }
 
-func newCreateS3IntegrationsCmd() *cobra.Command {
-	cmd := cobra.Command{
-		Use:         "integration NAME",
-		Short:       "create S3 integration",
-		Long:        "create S3 integration",
-		Args:        cobra.ExactArgs(1),
-		Annotations: group("integration"),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := cmd.Context()
-			rs, err := config.Client(cmd, Version)
-			if err != nil {
-				return err
-			}
-
-			role, _ := cmd.Flags().GetString(flag.RoleARN)
-			result, err := rs.CreateS3Integration(ctx, args[0], option.AWSRole(role))
-			if err != nil {
-				return err
-			}
-
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "integration'%s' is created\n", result.Name)
-
-			return nil
-		},
-	}
-
-	cmd.Flags().String(flag.RoleARN, "", "AWS IAM role ARN")
-	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.RoleARN)
-
-	return &cmd
-}
-
 func newDeleteIntegrationsCmd() *cobra.Command {
 	cmd := cobra.Command{
 		Use:         "integration NAME",
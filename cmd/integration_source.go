@@ -0,0 +1,698 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/rockset/rockset-go-client"
+	rockerr "github.com/rockset/rockset-go-client/errors"
+	"github.com/rockset/rockset-go-client/openapi"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/rockset/cli/completion"
+	"github.com/rockset/cli/config"
+	"github.com/rockset/cli/flag"
+)
+
+// credentials are read from the --credentials-file, or from these environment variables, except for the AWS
+// access keys which are only read from the file
+const (
+	envAWSAccessKeyID        = "AWS_ACCESS_KEY_ID"
+	envAWSSecretAccessKey    = "AWS_SECRET_ACCESS_KEY"
+	envAzureConnectionString = "AZURE_STORAGE_CONNECTION_STRING"
+	envGoogleCredentials     = "GOOGLE_APPLICATION_CREDENTIALS"
+	envKafkaAPIKey           = "KAFKA_API_KEY"
+	envKafkaAPISecret        = "KAFKA_API_SECRET"
+	envMongoDBConnectionURI  = "MONGODB_CONNECTION_URI"
+	envSchemaRegistryKey     = "SCHEMA_REGISTRY_KEY"
+	envSchemaRegistrySecret  = "SCHEMA_REGISTRY_SECRET"
+	envSnowflakePassword     = "SNOWFLAKE_PASSWORD"
+)
+
+// credentialsHelp explains how credentials are read, given the environment variables used
+func credentialsHelp(env []string) string {
+	return fmt.Sprintf(`Credentials are not passed as flags, they are read from the environment variables
+
+  %s
+
+or from a YAML file given with --%s, which uses the environment variable names as keys, e.g.
+
+  %s: ...`, strings.Join(env, "\n  "), flag.CredentialsFile, env[0])
+}
+
+// awsCredentialsHelp explains how the AWS credentials are given, as the access keys aren't read from the environment
+var awsCredentialsHelp = fmt.Sprintf(`The AWS credentials are either an IAM role given with --%s, or access keys in a YAML file
+given with --%s, e.g.
+
+  %s: ...
+  %s: ...
+
+The access keys are not read from the environment, so the keys of whoever runs the command aren't stored in
+the integration by accident.`, flag.RoleARN, flag.CredentialsFile, envAWSAccessKeyID, envAWSSecretAccessKey)
+
+// integrationFn sets the integration specific part of the request
+type integrationFn func(cmd *cobra.Command, creds credentials, req *openapi.CreateIntegrationRequest) error
+
+func newCreateS3IntegrationsCmd() *cobra.Command {
+	cmd := sourceIntegrationCmd("S3", nil,
+		func(cmd *cobra.Command, creds credentials, req *openapi.CreateIntegrationRequest) error {
+			role, keys, err := awsCredentials(cmd, creds)
+			if err != nil {
+				return err
+			}
+			req.S3 = &openapi.S3Integration{AwsRole: role, AwsAccessKey: keys}
+
+			return nil
+		})
+	addAWSCredentialFlags(cmd)
+	cmd.Long += ".\n\n" + awsCredentialsHelp
+
+	return cmd
+}
+
+func newCreateKafkaIntegrationCmd() *cobra.Command {
+	cmd := sourceIntegrationCmd("Kafka", []string{envKafkaAPIKey, envKafkaAPISecret, envSchemaRegistryKey,
+		envSchemaRegistrySecret},
+		func(cmd *cobra.Command, creds credentials, req *openapi.CreateIntegrationRequest) error {
+			servers, _ := cmd.Flags().GetString(flag.BootstrapServers)
+			req.Kafka = &openapi.KafkaIntegration{
+				BootstrapServers: &servers,
+				UseV3:            openapi.PtrBool(true),
+			}
+
+			if url, _ := cmd.Flags().GetString(flag.SchemaRegistryURL); url != "" {
+				req.Kafka.SchemaRegistryConfig = &openapi.SchemaRegistryConfig{Url: &url}
+			}
+
+			return setKafkaCredentials(req.Kafka, creds)
+		})
+	cmd.Long += "\n\nThe integration uses the v3 connector, for Confluent Cloud or any Kafka cluster with SASL/PLAIN " +
+		"authentication."
+
+	cmd.Flags().String(flag.BootstrapServers, "", "comma separated list of Kafka bootstrap servers")
+	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.BootstrapServers)
+	cmd.Flags().String(flag.SchemaRegistryURL, "", "URL of the schema registry, used for Avro")
+
+	return cmd
+}
+
+func newCreateMSKIntegrationCmd() *cobra.Command {
+	cmd := sourceIntegrationCmd("MSK", nil,
+		func(cmd *cobra.Command, _ credentials, req *openapi.CreateIntegrationRequest) error {
+			servers, _ := cmd.Flags().GetString(flag.BootstrapServers)
+			role, _ := cmd.Flags().GetString(flag.RoleARN)
+			req.Kafka = &openapi.KafkaIntegration{
+				BootstrapServers: &servers,
+				AwsRole:          &openapi.AwsRole{AwsRoleArn: role},
+				UseV3:            openapi.PtrBool(true),
+			}
+
+			return nil
+		})
+
+	cmd.Flags().String(flag.BootstrapServers, "", "comma separated list of MSK bootstrap servers")
+	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.BootstrapServers)
+	cmd.Flags().String(flag.RoleARN, "", "AWS IAM role ARN with access to the MSK cluster")
+	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.RoleARN)
+
+	return cmd
+}
+
+func newCreateKinesisIntegrationCmd() *cobra.Command {
+	cmd := sourceIntegrationCmd("Kinesis", nil,
+		func(cmd *cobra.Command, creds credentials, req *openapi.CreateIntegrationRequest) error {
+			role, keys, err := awsCredentials(cmd, creds)
+			if err != nil {
+				return err
+			}
+			req.Kinesis = &openapi.KinesisIntegration{AwsRole: role, AwsAccessKey: keys}
+
+			return nil
+		})
+	addAWSCredentialFlags(cmd)
+	cmd.Long += ".\n\n" + awsCredentialsHelp
+
+	return cmd
+}
+
+func newCreateDynamoDBIntegrationCmd() *cobra.Command {
+	cmd := sourceIntegrationCmd("DynamoDB", nil,
+		func(cmd *cobra.Command, creds credentials, req *openapi.CreateIntegrationRequest) error {
+			role, keys, err := awsCredentials(cmd, creds)
+			if err != nil {
+				return err
+			}
+			bucket, _ := cmd.Flags().GetString(flag.S3ExportBucket)
+			req.Dynamodb = &openapi.DynamodbIntegration{
+				AwsRole:            role,
+				AwsAccessKey:       keys,
+				S3ExportBucketName: optionalString(bucket),
+			}
+
+			return nil
+		})
+	addAWSCredentialFlags(cmd)
+	cmd.Long += ".\n\n" + awsCredentialsHelp
+	cmd.Flags().String(flag.S3ExportBucket, "", "S3 bucket used to export the table for the initial load")
+
+	return cmd
+}
+
+func newCreateMongoDBIntegrationCmd() *cobra.Command {
+	return sourceIntegrationCmd("MongoDB", []string{envMongoDBConnectionURI},
+		func(_ *cobra.Command, creds credentials, req *openapi.CreateIntegrationRequest) error {
+			req.Mongodb = &openapi.MongoDbIntegration{}
+
+			return setMongoDBCredentials(req.Mongodb, creds)
+		})
+}
+
+func newCreateGCSIntegrationCmd() *cobra.Command {
+	cmd := sourceIntegrationCmd("GCS", []string{envGoogleCredentials},
+		func(cmd *cobra.Command, creds credentials, req *openapi.CreateIntegrationRequest) error {
+			req.Gcs = &openapi.GcsIntegration{}
+
+			keyFile, _ := cmd.Flags().GetString(flag.ServiceAccountKeyFile)
+			return setGCSCredentials(keyFile, req.Gcs, creds)
+		})
+	cmd.Long += fmt.Sprintf("\n\n%s is the path of the service account key file, and can be overridden by --%s.",
+		envGoogleCredentials, flag.ServiceAccountKeyFile)
+	addGCSCredentialFlags(cmd)
+
+	return cmd
+}
+
+func newCreateAzureIntegrationCmd() *cobra.Command {
+	return sourceIntegrationCmd("Azure Blob Storage", []string{envAzureConnectionString},
+		func(_ *cobra.Command, creds credentials, req *openapi.CreateIntegrationRequest) error {
+			req.AzureBlobStorage = &openapi.AzureBlobStorageIntegration{}
+
+			return setAzureCredentials(req.AzureBlobStorage, creds)
+		})
+}
+
+func newCreateSnowflakeIntegrationCmd() *cobra.Command {
+	cmd := sourceIntegrationCmd("Snowflake", []string{envSnowflakePassword},
+		func(cmd *cobra.Command, creds credentials, req *openapi.CreateIntegrationRequest) error {
+			url, _ := cmd.Flags().GetString(flag.URL)
+			username, _ := cmd.Flags().GetString(flag.Username)
+			warehouse, _ := cmd.Flags().GetString(flag.Warehouse)
+			exportPath, _ := cmd.Flags().GetString(flag.S3ExportPath)
+			userRole, _ := cmd.Flags().GetString(flag.UserRole)
+
+			role, keys, err := awsCredentials(cmd, creds)
+			if err != nil {
+				return err
+			}
+
+			req.Snowflake = &openapi.SnowflakeIntegration{
+				SnowflakeUrl:     url,
+				Username:         username,
+				DefaultWarehouse: warehouse,
+				S3ExportPath:     exportPath,
+				UserRole:         optionalString(userRole),
+				AwsRole:          role,
+				AwsAccessKey:     keys,
+			}
+
+			return setSnowflakeCredentials(req.Snowflake, creds)
+		})
+	cmd.Long += "\n\nThe AWS credentials are used to access the S3 bucket the Snowflake table is exported to. " +
+		awsCredentialsHelp
+
+	cmd.Flags().String(flag.URL, "", "Snowflake URL, e.g. https://ACCOUNT.snowflakecomputing.com")
+	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.URL)
+	cmd.Flags().String(flag.Username, "", "Snowflake user name")
+	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.Username)
+	cmd.Flags().String(flag.Warehouse, "", "default Snowflake warehouse")
+	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.Warehouse)
+	cmd.Flags().String(flag.S3ExportPath, "", "S3 path the Snowflake tables are exported to, e.g. s3://bucket/prefix")
+	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.S3ExportPath)
+	cmd.Flags().String(flag.UserRole, "", "Snowflake role of the user")
+	addAWSCredentialFlags(cmd)
+
+	return cmd
+}
+
+func newUpdateIntegrationCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "integration NAME",
+		Short: "update integration",
+		Long: `update the credentials or description of an integration, e.g. to rotate the credentials.
+
+The settings other than the credentials are kept as they are, and the credentials are read in the same
+way as when the integration is created, see the help of the create integration command of the source.
+The credentials are only changed if new ones are given, so the description can be updated on its own.
+
+The AWS credentials are changed using --role-arn, or the AWS access keys in the --credentials-file, as the
+keys in the environment are ignored. A Snowflake integration requires the password for any change of the
+credentials, as the API replaces all of its settings.`,
+		Example: `	## rotate the credentials of a Kafka integration
+	KAFKA_API_KEY=... KAFKA_API_SECRET=... rockset update integration confluent
+
+	## rotate the credentials using a file
+	rockset update integration mongo --credentials-file mongo.yaml
+
+	## only update the description
+	rockset update integration s3 --description "production bucket"`,
+		Args:              cobra.ExactArgs(1),
+		Annotations:       group("integration"),
+		ValidArgsFunction: completion.Integration(Version),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			name := args[0]
+
+			creds, err := loadCredentials(cmd)
+			if err != nil {
+				return err
+			}
+
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			current, err := rs.GetIntegration(ctx, name)
+			if err != nil {
+				return err
+			}
+
+			roleARN, _ := cmd.Flags().GetString(flag.RoleARN)
+			keyFile, _ := cmd.Flags().GetString(flag.ServiceAccountKeyFile)
+			req, err := IntegrationUpdate(current, creds, roleARN, keyFile)
+			if err != nil {
+				return err
+			}
+			if description, _ := cmd.Flags().GetString(flag.Description); cmd.Flags().Changed(flag.Description) {
+				req.Description = &description
+			}
+			if req == (openapi.UpdateIntegrationRequest{}) {
+				return fmt.Errorf("nothing to update, specify new credentials or a description")
+			}
+
+			if err = updateIntegration(ctx, rs, name, req); err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "integration '%s' updated\n", name)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flag.Description, "", "integration description")
+	addCredentialsFileFlag(&cmd)
+	addAWSCredentialFlags(&cmd)
+	addGCSCredentialFlags(&cmd)
+
+	return &cmd
+}
+
+// IntegrationUpdate returns the update request for the integration, which only includes the source settings
+// if new credentials are given, so the description can be updated on its own. The source settings are built
+// from the credentials, and the settings the API requires in the update, so the server-set and redacted fields
+// of the current integration aren't sent back. An integration using an AWS IAM role keeps it unless a new role
+// or access keys are given.
+func IntegrationUpdate(current openapi.Integration, creds credentials,
+	roleARN, keyFile string) (openapi.UpdateIntegrationRequest, error) {
+	var req openapi.UpdateIntegrationRequest
+
+	switch {
+	case current.S3 != nil:
+		role, keys, err := awsUpdate(creds, roleARN)
+		if err != nil || (role == nil && keys == nil) {
+			return req, err
+		}
+		req.S3 = &openapi.S3Integration{AwsRole: role, AwsAccessKey: keys}
+	case current.Kinesis != nil:
+		role, keys, err := awsUpdate(creds, roleARN)
+		if err != nil || (role == nil && keys == nil) {
+			return req, err
+		}
+		req.Kinesis = &openapi.KinesisIntegration{AwsRole: role, AwsAccessKey: keys}
+	case current.Dynamodb != nil:
+		role, keys, err := awsUpdate(creds, roleARN)
+		if err != nil || (role == nil && keys == nil) {
+			return req, err
+		}
+		req.Dynamodb = &openapi.DynamodbIntegration{
+			AwsRole:            role,
+			AwsAccessKey:       keys,
+			S3ExportBucketName: current.Dynamodb.S3ExportBucketName,
+		}
+	case current.Kafka != nil && current.Kafka.AwsRole != nil:
+		// MSK only uses a role
+		if roleARN != "" {
+			req.Kafka = &openapi.KafkaIntegration{
+				AwsRole:          &openapi.AwsRole{AwsRoleArn: roleARN},
+				BootstrapServers: current.Kafka.BootstrapServers,
+				UseV3:            current.Kafka.UseV3,
+			}
+		}
+	case current.Kafka != nil:
+		if creds.has(envKafkaAPIKey, envKafkaAPISecret, envSchemaRegistryKey, envSchemaRegistrySecret) {
+			req.Kafka = &openapi.KafkaIntegration{
+				BootstrapServers: current.Kafka.BootstrapServers,
+				UseV3:            current.Kafka.UseV3,
+			}
+			if url := current.Kafka.SchemaRegistryConfig.GetUrl(); url != "" {
+				req.Kafka.SchemaRegistryConfig = &openapi.SchemaRegistryConfig{Url: &url}
+			}
+			return req, setKafkaCredentials(req.Kafka, creds)
+		}
+	case current.Mongodb != nil:
+		if creds.has(envMongoDBConnectionURI) {
+			req.Mongodb = &openapi.MongoDbIntegration{Tls: current.Mongodb.Tls}
+			return req, setMongoDBCredentials(req.Mongodb, creds)
+		}
+	case current.Gcs != nil:
+		if keyFile != "" || creds.has(envGoogleCredentials) {
+			req.Gcs = &openapi.GcsIntegration{}
+			return req, setGCSCredentials(keyFile, req.Gcs, creds)
+		}
+	case current.AzureBlobStorage != nil:
+		if creds.has(envAzureConnectionString) {
+			req.AzureBlobStorage = &openapi.AzureBlobStorageIntegration{}
+			return req, setAzureCredentials(req.AzureBlobStorage, creds)
+		}
+	case current.Snowflake != nil:
+		role, keys, err := awsUpdate(creds, roleARN)
+		if err != nil {
+			return req, err
+		}
+		if role == nil && keys == nil && !creds.has(envSnowflakePassword) {
+			return req, nil
+		}
+		// the API requires all the settings, including the password, which can't be taken from the current
+		// integration as it is redacted
+		req.Snowflake = &openapi.SnowflakeIntegration{
+			SnowflakeUrl:     current.Snowflake.SnowflakeUrl,
+			Username:         current.Snowflake.Username,
+			DefaultWarehouse: current.Snowflake.DefaultWarehouse,
+			S3ExportPath:     current.Snowflake.S3ExportPath,
+			UserRole:         current.Snowflake.UserRole,
+			AwsRole:          role,
+			AwsAccessKey:     keys,
+		}
+		if role == nil && keys == nil {
+			req.Snowflake.AwsRole, req.Snowflake.AwsAccessKey = current.Snowflake.AwsRole, nil
+			if req.Snowflake.AwsRole == nil {
+				return req, fmt.Errorf("the AWS access keys of Snowflake integration %s are required to change "+
+					"the password, use --%s or set %s and %s in the --%s", current.Name, flag.RoleARN,
+					envAWSAccessKeyID, envAWSSecretAccessKey, flag.CredentialsFile)
+			}
+		}
+		return req, setSnowflakeCredentials(req.Snowflake, creds)
+	default:
+		if roleARN != "" || keyFile != "" || len(creds) > 0 {
+			return req, fmt.Errorf("updating the credentials of integration %s is not supported", current.Name)
+		}
+	}
+
+	return req, nil
+}
+
+// sourceIntegrationCmd returns a command which creates an integration, where fn adds the source specific
+// settings to the request, and env lists the environment variables the credentials are read from
+func sourceIntegrationCmd(source string, env []string, fn integrationFn) *cobra.Command {
+	long := fmt.Sprintf("create %s integration", source)
+	if len(env) > 0 {
+		long += ".\n\n" + credentialsHelp(env)
+	}
+
+	cmd := cobra.Command{
+		Use:         "integration NAME",
+		Short:       fmt.Sprintf("create %s integration", source),
+		Long:        long,
+		Args:        cobra.ExactArgs(1),
+		Annotations: group("integration"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			name := args[0]
+
+			creds, err := loadCredentials(cmd)
+			if err != nil {
+				return err
+			}
+
+			req := openapi.NewCreateIntegrationRequest(name)
+			if description, _ := cmd.Flags().GetString(flag.Description); description != "" {
+				req.Description = &description
+			}
+			if err = fn(cmd, creds, req); err != nil {
+				return err
+			}
+
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			result, err := createIntegration(ctx, rs, *req)
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "integration '%s' is created\n", result.Name)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flag.Description, "", "integration description")
+	if len(env) > 0 {
+		addCredentialsFileFlag(&cmd)
+	}
+
+	return &cmd
+}
+
+// createIntegration uses the API directly, as the client only has helpers for some of the integration types
+func createIntegration(ctx context.Context, rs *rockset.RockClient,
+	req openapi.CreateIntegrationRequest) (openapi.Integration, error) {
+	var resp *openapi.CreateIntegrationResponse
+
+	err := rs.Retry(ctx, func() error {
+		var err error
+		var httpResp *http.Response
+		resp, httpResp, err = rs.IntegrationsApi.CreateIntegration(ctx).Body(req).Execute()
+
+		return rockerr.NewWithStatusCode(err, httpResp)
+	})
+	if err != nil {
+		return openapi.Integration{}, err
+	}
+
+	return resp.GetData(), nil
+}
+
+// updateIntegration uses the API directly, as the client doesn't support updating integrations
+func updateIntegration(ctx context.Context, rs *rockset.RockClient, name string,
+	req openapi.UpdateIntegrationRequest) error {
+	return rs.Retry(ctx, func() error {
+		_, httpResp, err := rs.IntegrationsApi.UpdateIntegration(ctx, name).Body(req).Execute()
+
+		return rockerr.NewWithStatusCode(err, httpResp)
+	})
+}
+
+// credentials are secrets read from the --credentials-file, falling back to the environment
+type credentials map[string]string
+
+func addCredentialsFileFlag(cmd *cobra.Command) {
+	cmd.Flags().String(flag.CredentialsFile, "", "read credentials from YAML file instead of the environment")
+	_ = cobra.MarkFlagFilename(cmd.Flags(), flag.CredentialsFile, ".yaml", ".yml")
+}
+
+func loadCredentials(cmd *cobra.Command) (credentials, error) {
+	file, _ := cmd.Flags().GetString(flag.CredentialsFile)
+	if file == "" {
+		return credentials{}, nil
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials: %w", err)
+	}
+
+	return ParseCredentials(data)
+}
+
+// ParseCredentials parses a YAML credentials file, which maps the names of the environment variables to their values
+func ParseCredentials(data []byte) (map[string]string, error) {
+	var creds map[string]string
+	if err := yaml.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials: %w", err)
+	}
+	if creds == nil {
+		creds = map[string]string{}
+	}
+
+	return creds, nil
+}
+
+// get returns the credential from the file, or from the environment
+func (c credentials) get(key string) string {
+	if v, ok := c[key]; ok {
+		return v
+	}
+
+	return os.Getenv(key)
+}
+
+// has returns true if any of the keys is in the credentials file or the environment
+func (c credentials) has(keys ...string) bool {
+	for _, k := range keys {
+		if c.get(k) != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// require returns the credentials for the keys, and an error listing all which are missing
+func (c credentials) require(keys ...string) ([]string, error) {
+	values := make([]string, len(keys))
+	var missing []string
+	for i, k := range keys {
+		if values[i] = c.get(k); values[i] == "" {
+			missing = append(missing, k)
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing credentials, set %s in the environment or the --%s",
+			strings.Join(missing, ", "), flag.CredentialsFile)
+	}
+
+	return values, nil
+}
+
+// addAWSCredentialFlags adds --role-arn, and the --credentials-file for the access keys unless it is already added
+func addAWSCredentialFlags(cmd *cobra.Command) {
+	cmd.Flags().String(flag.RoleARN, "", fmt.Sprintf("AWS IAM role ARN, if not set %s and %s are read from the --%s",
+		envAWSAccessKeyID, envAWSSecretAccessKey, flag.CredentialsFile))
+	if cmd.Flags().Lookup(flag.CredentialsFile) == nil {
+		addCredentialsFileFlag(cmd)
+	}
+}
+
+// awsCredentials returns the role if --role-arn is set, otherwise the access keys from the credentials file
+func awsCredentials(cmd *cobra.Command, creds credentials) (*openapi.AwsRole, *openapi.AwsAccessKey, error) {
+	if role, _ := cmd.Flags().GetString(flag.RoleARN); role != "" {
+		return &openapi.AwsRole{AwsRoleArn: role}, nil, nil
+	}
+
+	id, secret := creds[envAWSAccessKeyID], creds[envAWSSecretAccessKey]
+	if id == "" || secret == "" {
+		return nil, nil, fmt.Errorf("use --%s, or set %s and %s in the --%s", flag.RoleARN,
+			envAWSAccessKeyID, envAWSSecretAccessKey, flag.CredentialsFile)
+	}
+
+	return nil, &openapi.AwsAccessKey{AwsAccessKeyId: id, AwsSecretAccessKey: secret}, nil
+}
+
+// awsUpdate returns the new role or access keys, or neither if no new credentials are given. As when creating
+// the integration, the access keys are only read from the credentials file and never from the environment.
+func awsUpdate(creds credentials, roleARN string) (*openapi.AwsRole, *openapi.AwsAccessKey, error) {
+	if roleARN != "" {
+		return &openapi.AwsRole{AwsRoleArn: roleARN}, nil, nil
+	}
+
+	id, secret := creds[envAWSAccessKeyID], creds[envAWSSecretAccessKey]
+	switch {
+	case id == "" && secret == "":
+		return nil, nil, nil
+	case id == "" || secret == "":
+		return nil, nil, fmt.Errorf("both %s and %s are required to change the access keys",
+			envAWSAccessKeyID, envAWSSecretAccessKey)
+	}
+
+	return nil, &openapi.AwsAccessKey{AwsAccessKeyId: id, AwsSecretAccessKey: secret}, nil
+}
+
+func setKafkaCredentials(k *openapi.KafkaIntegration, creds credentials) error {
+	keys, err := creds.require(envKafkaAPIKey, envKafkaAPISecret)
+	if err != nil {
+		return err
+	}
+	k.SecurityConfig = &openapi.KafkaV3SecurityConfig{ApiKey: &keys[0], Secret: &keys[1]}
+
+	if k.SchemaRegistryConfig == nil || k.SchemaRegistryConfig.GetUrl() == "" {
+		return nil
+	}
+
+	keys, err = creds.require(envSchemaRegistryKey, envSchemaRegistrySecret)
+	if err != nil {
+		return err
+	}
+	k.SchemaRegistryConfig.Key = &keys[0]
+	k.SchemaRegistryConfig.Secret = &keys[1]
+
+	return nil
+}
+
+func setMongoDBCredentials(m *openapi.MongoDbIntegration, creds credentials) error {
+	keys, err := creds.require(envMongoDBConnectionURI)
+	if err != nil {
+		return err
+	}
+	m.ConnectionUri = keys[0]
+
+	return nil
+}
+
+func setAzureCredentials(a *openapi.AzureBlobStorageIntegration, creds credentials) error {
+	keys, err := creds.require(envAzureConnectionString)
+	if err != nil {
+		return err
+	}
+	a.ConnectionString = keys[0]
+
+	return nil
+}
+
+func setSnowflakeCredentials(s *openapi.SnowflakeIntegration, creds credentials) error {
+	keys, err := creds.require(envSnowflakePassword)
+	if err != nil {
+		return err
+	}
+	s.Password = keys[0]
+
+	return nil
+}
+
+func addGCSCredentialFlags(cmd *cobra.Command) {
+	cmd.Flags().String(flag.ServiceAccountKeyFile, "", "GCP service account key file, defaults to "+
+		envGoogleCredentials)
+	_ = cobra.MarkFlagFilename(cmd.Flags(), flag.ServiceAccountKeyFile, ".json")
+}
+
+// setGCSCredentials reads the service account key file, which is the path given by the flag or in the credentials
+func setGCSCredentials(path string, g *openapi.GcsIntegration, creds credentials) error {
+	if path == "" {
+		path = creds.get(envGoogleCredentials)
+	}
+	if path == "" {
+		return fmt.Errorf("use --%s or set %s to the service account key file", flag.ServiceAccountKeyFile,
+			envGoogleCredentials)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read service account key: %w", err)
+	}
+	if !json.Valid(data) {
+		return errors.New("the service account key file must be JSON")
+	}
+	g.GcpServiceAccount = &openapi.GcpServiceAccount{ServiceAccountKeyFileJson: string(data)}
+
+	return nil
+}
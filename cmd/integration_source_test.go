@@ -0,0 +1,132 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/rockset/rockset-go-client/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/cli/cmd"
+)
+
+func TestParseCredentials(t *testing.T) {
+	creds, err := cmd.ParseCredentials([]byte(`
+KAFKA_API_KEY: key
+KAFKA_API_SECRET: "s3cr3t:with:colons"
+`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"KAFKA_API_KEY":    "key",
+		"KAFKA_API_SECRET": "s3cr3t:with:colons",
+	}, creds)
+
+	creds, err = cmd.ParseCredentials(nil)
+	require.NoError(t, err)
+	assert.Empty(t, creds)
+
+	_, err = cmd.ParseCredentials([]byte("- not a map"))
+	assert.Error(t, err)
+}
+
+func TestIntegrationUpdate(t *testing.T) {
+	role := &openapi.AwsRole{AwsRoleArn: "arn:aws:iam::123456789012:role/rockset"}
+	current := func() openapi.Integration {
+		return openapi.Integration{Name: "s3", S3: &openapi.S3Integration{AwsRole: role}}
+	}
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "env-id")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "env-secret")
+
+	t.Run("keeps role", func(t *testing.T) {
+		req, err := cmd.IntegrationUpdate(current(), map[string]string{}, "", "")
+		require.NoError(t, err)
+		assert.Nil(t, req.S3, "the role must not be replaced by the access keys in the environment")
+	})
+
+	t.Run("new role", func(t *testing.T) {
+		req, err := cmd.IntegrationUpdate(current(), map[string]string{}, "arn:aws:iam::123456789012:role/new", "")
+		require.NoError(t, err)
+		require.NotNil(t, req.S3)
+		assert.Equal(t, "arn:aws:iam::123456789012:role/new", req.S3.AwsRole.AwsRoleArn)
+		assert.Nil(t, req.S3.AwsAccessKey)
+	})
+
+	t.Run("keys from file", func(t *testing.T) {
+		req, err := cmd.IntegrationUpdate(current(), map[string]string{
+			"AWS_ACCESS_KEY_ID":     "file-id",
+			"AWS_SECRET_ACCESS_KEY": "file-secret",
+		}, "", "")
+		require.NoError(t, err)
+		require.NotNil(t, req.S3)
+		assert.Nil(t, req.S3.AwsRole)
+		assert.Equal(t, "file-id", req.S3.AwsAccessKey.AwsAccessKeyId)
+	})
+
+	t.Run("keys in the environment are ignored", func(t *testing.T) {
+		keys := openapi.Integration{Name: "s3", S3: &openapi.S3Integration{
+			AwsAccessKey: &openapi.AwsAccessKey{AwsAccessKeyId: "old"},
+		}}
+		req, err := cmd.IntegrationUpdate(keys, map[string]string{}, "", "")
+		require.NoError(t, err)
+		assert.Nil(t, req.S3)
+	})
+
+	t.Run("only mutable fields", func(t *testing.T) {
+		dynamo := openapi.Integration{Name: "dynamo", Dynamodb: &openapi.DynamodbIntegration{
+			AwsAccessKey:       &openapi.AwsAccessKey{AwsAccessKeyId: "old", AwsSecretAccessKey: "<redacted>"},
+			S3ExportBucketName: openapi.PtrString("bucket"),
+		}}
+		req, err := cmd.IntegrationUpdate(dynamo, map[string]string{}, "arn:aws:iam::123456789012:role/new", "")
+		require.NoError(t, err)
+		assert.Equal(t, &openapi.DynamodbIntegration{
+			AwsRole:            &openapi.AwsRole{AwsRoleArn: "arn:aws:iam::123456789012:role/new"},
+			S3ExportBucketName: openapi.PtrString("bucket"),
+		}, req.Dynamodb)
+
+		kafka := openapi.Integration{Name: "kafka", Kafka: &openapi.KafkaIntegration{
+			BootstrapServers:    openapi.PtrString("broker:9092"),
+			UseV3:               openapi.PtrBool(true),
+			KafkaTopicNames:     []string{"topic"},
+			SourceStatusByTopic: &map[string]openapi.StatusKafka{"topic": {}},
+			SecurityConfig:      &openapi.KafkaV3SecurityConfig{ApiKey: openapi.PtrString("old")},
+		}}
+		req, err = cmd.IntegrationUpdate(kafka, map[string]string{
+			"KAFKA_API_KEY":    "key",
+			"KAFKA_API_SECRET": "secret",
+		}, "", "")
+		require.NoError(t, err)
+		assert.Equal(t, &openapi.KafkaIntegration{
+			BootstrapServers: openapi.PtrString("broker:9092"),
+			UseV3:            openapi.PtrBool(true),
+			SecurityConfig: &openapi.KafkaV3SecurityConfig{
+				ApiKey: openapi.PtrString("key"),
+				Secret: openapi.PtrString("secret"),
+			},
+		}, req.Kafka)
+	})
+
+	t.Run("snowflake requires the password", func(t *testing.T) {
+		t.Setenv("SNOWFLAKE_PASSWORD", "")
+		snowflake := openapi.Integration{Name: "snowflake", Snowflake: &openapi.SnowflakeIntegration{
+			SnowflakeUrl: "https://account.snowflakecomputing.com",
+			AwsRole:      role,
+		}}
+		_, err := cmd.IntegrationUpdate(snowflake, map[string]string{}, "arn:aws:iam::123456789012:role/new", "")
+		assert.Error(t, err)
+
+		req, err := cmd.IntegrationUpdate(snowflake, map[string]string{"SNOWFLAKE_PASSWORD": "secret"}, "", "")
+		require.NoError(t, err)
+		require.NotNil(t, req.Snowflake)
+		assert.Equal(t, "https://account.snowflakecomputing.com", req.Snowflake.SnowflakeUrl)
+		assert.Equal(t, "secret", req.Snowflake.Password)
+		assert.Equal(t, role, req.Snowflake.AwsRole)
+	})
+
+	t.Run("nothing to update", func(t *testing.T) {
+		mongo := openapi.Integration{Name: "mongo", Mongodb: &openapi.MongoDbIntegration{}}
+		req, err := cmd.IntegrationUpdate(mongo, map[string]string{}, "", "")
+		require.NoError(t, err)
+		assert.Equal(t, openapi.UpdateIntegrationRequest{}, req)
+	})
+}
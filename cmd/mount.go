@@ -1,16 +1,26 @@
 package cmd
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/rockset/rockset-go-client"
+	"github.com/rockset/rockset-go-client/openapi"
+	"github.com/spf13/cobra"
+
 	"github.com/rockset/cli/completion"
 	"github.com/rockset/cli/config"
 	"github.com/rockset/cli/flag"
 	"github.com/rockset/cli/format"
 	"github.com/rockset/cli/lookup"
 	"github.com/rockset/cli/sort"
-	"github.com/rockset/rockset-go-client/openapi"
-	"github.com/spf13/cobra"
-	"strings"
 )
 
 func NewListMountsCmd() *cobra.Command {
@@ -91,10 +101,14 @@ func NewGetMountCmd() *cobra.Command {
 
 func NewMountCollectionsCmd() *cobra.Command {
 	cmd := cobra.Command{
-		Use:         "mount PATH",
-		Aliases:     []string{"m"},
-		Args:        cobra.MinimumNArgs(1),
-		Short:       "mount one or more collections on a virtual instance",
+		Use:     "mount PATH|PATTERN ...",
+		Aliases: []string{"m"},
+		Args:    cobra.MinimumNArgs(1),
+		Short:   "mount one or more collections on a virtual instance",
+		Long: `mount one or more collections on a virtual instance.
+A path can be a glob pattern, which is matched against all collections, e.g. 'prod.*'.`,
+		Example: `	## mount all collections in the prod workspace
+	rockset mount --vi analytics 'prod.*'`,
 		Annotations: group("mount"),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
@@ -109,7 +123,17 @@ func NewMountCollectionsCmd() *cobra.Command {
 				return err
 			}
 
-			mounts, err := rs.MountCollections(ctx, id, args)
+			paths, err := collectionPaths(ctx, rs)
+			if err != nil {
+				return err
+			}
+
+			matched, err := MatchCollectionPaths(args, paths)
+			if err != nil {
+				return err
+			}
+
+			mounts, err := rs.MountCollections(ctx, id, matched)
 			if err != nil {
 				return err
 			}
@@ -134,10 +158,12 @@ func NewMountCollectionsCmd() *cobra.Command {
 
 func NewUnmountCollectionCmd() *cobra.Command {
 	cmd := cobra.Command{
-		Use:               "unmount PATH",
-		Aliases:           []string{"m"},
-		Args:              cobra.MinimumNArgs(1),
-		Short:             "unmount a collection from a virtual instance",
+		Use:     "unmount PATH|PATTERN ...",
+		Aliases: []string{"m"},
+		Args:    cobra.MinimumNArgs(1),
+		Short:   "unmount one or more collections from a virtual instance",
+		Long: `unmount one or more collections from a virtual instance.
+A path can be a glob pattern, which is matched against the mounted collections, e.g. 'dev.*'.`,
 		Annotations:       group("mount"),
 		ValidArgsFunction: completion.CollectionMount(Version),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -153,14 +179,17 @@ func NewUnmountCollectionCmd() *cobra.Command {
 				return err
 			}
 
-			mount, err := rs.UnmountCollection(ctx, id, args[0])
+			mounted, err := mountedPaths(ctx, rs, id)
 			if err != nil {
 				return err
 			}
 
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "unmounted %s from %s\n", mount.GetCollectionPath(), vi)
+			matched, err := MatchCollectionPaths(args, mounted)
+			if err != nil {
+				return err
+			}
 
-			return nil
+			return unmountCollections(ctx, cmd, rs, id, vi, matched)
 		},
 	}
 
@@ -170,3 +199,225 @@ func NewUnmountCollectionCmd() *cobra.Command {
 
 	return &cmd
 }
+
+func newSyncMountsCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:     "mounts",
+		Aliases: []string{"m"},
+		Args:    cobra.NoArgs,
+		Short:   "sync the collections mounted on a virtual instance",
+		Long: `mount the collections listed in the file which aren't mounted on the virtual instance,
+and unmount the mounted collections which aren't listed.
+
+The file has one collection path or glob pattern per line, and lines starting with # are ignored.
+Unmounting collections has to be confirmed, unless --yes is used.`,
+		Example: `	## mounts.txt
+	# all production collections
+	prod.*
+	commons.movies
+
+	## show what would change
+	rockset sync mounts --vi analytics --file mounts.txt --dry-run
+
+	## sync without asking before unmounting collections
+	rockset sync mounts --vi analytics --file mounts.txt --yes`,
+		Annotations: group("mount"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			vi, _ := cmd.Flags().GetString(flag.VI)
+			file, _ := cmd.Flags().GetString(flag.File)
+			dryRun, _ := cmd.Flags().GetBool(flag.DryRun)
+			out := cmd.OutOrStdout()
+
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			patterns := ParseMountFile(data)
+
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			id, err := lookup.VirtualInstanceNameOrIDtoID(ctx, rs, vi)
+			if err != nil {
+				return err
+			}
+
+			paths, err := collectionPaths(ctx, rs)
+			if err != nil {
+				return err
+			}
+
+			desired, err := MatchCollectionPaths(patterns, paths)
+			if err != nil {
+				return err
+			}
+
+			mounted, err := mountedPaths(ctx, rs, id)
+			if err != nil {
+				return err
+			}
+
+			mount, unmount := MountChanges(desired, mounted)
+			if len(mount) == 0 && len(unmount) == 0 {
+				_, _ = fmt.Fprintf(out, "mounts on %s are in sync\n", vi)
+				return nil
+			}
+
+			if dryRun {
+				for _, p := range mount {
+					_, _ = fmt.Fprintf(out, "would mount %s\n", p)
+				}
+				for _, p := range unmount {
+					_, _ = fmt.Fprintf(out, "would unmount %s\n", p)
+				}
+				return nil
+			}
+
+			// an empty file unmounts everything, so ask before unmounting
+			if len(unmount) > 0 {
+				items := make([]string, len(unmount))
+				for i, p := range unmount {
+					items[i] = fmt.Sprintf("mount of %s on %s", p, vi)
+				}
+				if ok, err := confirm(cmd, "unmount", items); err != nil || !ok {
+					return err
+				}
+			}
+
+			if len(mount) > 0 {
+				if _, err = rs.MountCollections(ctx, id, mount); err != nil {
+					return err
+				}
+				_, _ = fmt.Fprintf(out, "mounted %s on %s\n", strings.Join(mount, ", "), vi)
+			}
+
+			return unmountCollections(ctx, cmd, rs, id, vi, unmount)
+		},
+	}
+
+	cmd.Flags().String(flag.VI, "", "virtual instance id or name")
+	_ = cmd.MarkFlagRequired(flag.VI)
+	_ = cmd.RegisterFlagCompletionFunc(flag.VI, completion.VirtualInstance(Version))
+	cmd.Flags().StringP(flag.File, "f", "", "file with the collections which should be mounted")
+	_ = cmd.MarkFlagRequired(flag.File)
+	cmd.Flags().Bool(flag.DryRun, false, "only show what would be mounted and unmounted")
+	cmd.Flags().BoolP(flag.Yes, "y", false, "unmount collections without asking for confirmation")
+
+	return &cmd
+}
+
+func unmountCollections(ctx context.Context, cmd *cobra.Command, rs *rockset.RockClient, id, vi string,
+	paths []string) error {
+	var errs []error
+	for _, p := range paths {
+		mount, err := rs.UnmountCollection(ctx, id, p)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to unmount %s: %w", p, err))
+			continue
+		}
+
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "unmounted %s from %s\n", mount.GetCollectionPath(), vi)
+	}
+
+	return errors.Join(errs...)
+}
+
+// collectionPaths returns the paths of all collections, as WORKSPACE.COLLECTION
+func collectionPaths(ctx context.Context, rs *rockset.RockClient) ([]string, error) {
+	collections, err := rs.ListCollections(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, len(collections))
+	for i, c := range collections {
+		paths[i] = fmt.Sprintf("%s.%s", c.GetWorkspace(), c.GetName())
+	}
+
+	return paths, nil
+}
+
+func mountedPaths(ctx context.Context, rs *rockset.RockClient, id string) ([]string, error) {
+	mounts, err := rs.ListCollectionMounts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, len(mounts))
+	for i, m := range mounts {
+		paths[i] = m.GetCollectionPath()
+	}
+
+	return paths, nil
+}
+
+// MatchCollectionPaths returns the paths matching the patterns, in the order of the patterns and without duplicates.
+// A pattern without glob characters is returned as is, so the API can report if it doesn't exist,
+// but a glob pattern which doesn't match any path is an error.
+func MatchCollectionPaths(patterns, paths []string) ([]string, error) {
+	var result []string
+	for _, pattern := range patterns {
+		if !strings.ContainsAny(pattern, "*?[") {
+			if !slices.Contains(result, pattern) {
+				result = append(result, pattern)
+			}
+			continue
+		}
+
+		var found bool
+		for _, p := range paths {
+			match, err := path.Match(pattern, p)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %s: %w", pattern, err)
+			}
+			if !match {
+				continue
+			}
+			found = true
+			if !slices.Contains(result, p) {
+				result = append(result, p)
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("no collections match %s", pattern)
+		}
+	}
+
+	return result, nil
+}
+
+// MountChanges returns the paths which need to be mounted and unmounted to go from the current to the desired mounts
+func MountChanges(desired, current []string) (mount, unmount []string) {
+	for _, p := range desired {
+		if !slices.Contains(current, p) {
+			mount = append(mount, p)
+		}
+	}
+	for _, p := range current {
+		if !slices.Contains(desired, p) {
+			unmount = append(unmount, p)
+		}
+	}
+
+	return mount, unmount
+}
+
+// ParseMountFile returns the collection paths and patterns in a mount file, ignoring blank lines and # comments
+func ParseMountFile(data []byte) []string {
+	var patterns []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+
+	return patterns
+}
@@ -0,0 +1,45 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/cli/cmd"
+)
+
+func TestMatchCollectionPaths(t *testing.T) {
+	paths := []string{"prod.users", "prod.orders", "dev.users", "commons.movies"}
+
+	matched, err := cmd.MatchCollectionPaths([]string{"prod.*", "prod.users", "commons.movies"}, paths)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prod.users", "prod.orders", "commons.movies"}, matched)
+
+	matched, err = cmd.MatchCollectionPaths([]string{"*.users"}, paths)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prod.users", "dev.users"}, matched)
+
+	// paths without glob characters are passed through
+	matched, err = cmd.MatchCollectionPaths([]string{"other.coll"}, paths)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"other.coll"}, matched)
+
+	_, err = cmd.MatchCollectionPaths([]string{"staging.*"}, paths)
+	assert.EqualError(t, err, "no collections match staging.*")
+}
+
+func TestMountChanges(t *testing.T) {
+	mount, unmount := cmd.MountChanges([]string{"a.a", "a.b", "a.c"}, []string{"a.b", "b.a"})
+	assert.Equal(t, []string{"a.a", "a.c"}, mount)
+	assert.Equal(t, []string{"b.a"}, unmount)
+
+	mount, unmount = cmd.MountChanges([]string{"a.a"}, []string{"a.a"})
+	assert.Empty(t, mount)
+	assert.Empty(t, unmount)
+}
+
+func TestParseMountFile(t *testing.T) {
+	patterns := cmd.ParseMountFile([]byte("# production\nprod.*\n\n  commons.movies  \n"))
+	assert.Equal(t, []string{"prod.*", "commons.movies"}, patterns)
+}
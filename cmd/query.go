@@ -195,18 +195,8 @@ func newQueryCmd() *cobra.Command {
 				return interactiveQuery(ctx, io.NopCloser(cmd.InOrStdin()), cmd.OutOrStdout(), rs)
 			}
 
-			if file != "" && len(args) > 0 {
-				return fmt.Errorf("you can only specify one of --file or a SQL query")
-			}
-
-			if file != "" {
-				data, err := os.ReadFile(file)
-				if err != nil {
-					return err
-				}
-				sql = string(data)
-			} else {
-				sql = args[0]
+			if sql, err = sqlFromArgsOrFile(args, file); err != nil {
+				return err
 			}
 
 			if validate {
@@ -258,6 +248,27 @@ func newQueryCmd() *cobra.Command {
 	return &cmd
 }
 
+// sqlFromArgsOrFile returns the SQL from either the only argument, or read from the file
+func sqlFromArgsOrFile(args []string, file string) (string, error) {
+	if file != "" && len(args) > 0 {
+		return "", fmt.Errorf("you can only specify one of --file or a SQL query")
+	}
+
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+
+	if len(args) == 0 {
+		return "", fmt.Errorf("you must specify either --file or a SQL query")
+	}
+
+	return args[0], nil
+}
+
 func showQueryPaginationResponse(out io.Writer, cursor string, elapsedMs int64, results []map[string]interface{}) error {
 	if len(results) == 0 {
 		return errors.New("query returned no rows")
@@ -276,27 +287,12 @@ func showQueryPaginationResponse(out io.Writer, cursor string, elapsedMs int64,
 func showQueryResponse(out io.Writer, result openapi.QueryResponse) error {
 	switch result.GetStatus() {
 	case "ERROR":
-		var errs []string
-		for _, e := range result.GetQueryErrors() {
-			errs = append(errs, e.GetMessage())
-		}
-		_, _ = fmt.Fprintf(out, "query %s failed:\n%s\n", result.GetQueryId(), strings.Join(errs, "\n"))
+		_, _ = fmt.Fprintf(out, "%s\n", queryResponseError(result))
 	case "QUEUED", "RUNNING":
 		_, _ = fmt.Fprintf(out, "your query %s is %s\n", result.GetQueryId(), result.GetStatus())
 	case "COMPLETED":
-		var headers []string
-		if len(result.GetColumnFields()) == 0 {
-			// in a "SELECT *" query the ColumnFields isn't populated what order should the columns be presented in?
-			for h := range result.Results[0] {
-				headers = append(headers, h)
-			}
-		} else {
-			for _, h := range result.GetColumnFields() {
-				headers = append(headers, h.Name)
-			}
-		}
 		stats := result.GetStats()
-		showQueryResponseTable(out, "", stats.GetElapsedTimeMs(), headers, result.Results)
+		showQueryResponseTable(out, "", stats.GetElapsedTimeMs(), queryResponseHeaders(result), result.Results)
 	default:
 		return fmt.Errorf("unexpected query status: %s", result.GetStatus())
 	}
@@ -304,6 +300,35 @@ func showQueryResponse(out io.Writer, result openapi.QueryResponse) error {
 	return nil
 }
 
+// queryResponseError returns an error containing all error messages of a failed query
+func queryResponseError(result openapi.QueryResponse) error {
+	var errs []string
+	for _, e := range result.GetQueryErrors() {
+		errs = append(errs, e.GetMessage())
+	}
+
+	return fmt.Errorf("query %s failed:\n%s", result.GetQueryId(), strings.Join(errs, "\n"))
+}
+
+// queryResponseHeaders returns the column names of a completed query
+func queryResponseHeaders(result openapi.QueryResponse) []string {
+	var headers []string
+	if len(result.GetColumnFields()) == 0 {
+		// in a "SELECT *" query the ColumnFields isn't populated what order should the columns be presented in?
+		if len(result.Results) > 0 {
+			for h := range result.Results[0] {
+				headers = append(headers, h)
+			}
+		}
+	} else {
+		for _, h := range result.GetColumnFields() {
+			headers = append(headers, h.Name)
+		}
+	}
+
+	return headers
+}
+
 func showQueryResponseTable(out io.Writer, cursor string, elapsedMs int64, headers []string, results []map[string]interface{}) {
 	t := tui.NewTable(out)
 	t.Headers(headers...)
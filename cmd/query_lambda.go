@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	gosort "sort"
 	"strings"
 
 	"github.com/rockset/rockset-go-client"
@@ -12,9 +14,11 @@ import (
 
 	"github.com/rockset/cli/completion"
 	"github.com/rockset/cli/config"
+	"github.com/rockset/cli/diff"
 	"github.com/rockset/cli/flag"
 	"github.com/rockset/cli/format"
 	"github.com/rockset/cli/sort"
+	"github.com/rockset/cli/wait"
 )
 
 func newListQueryLambdasCmd() *cobra.Command {
@@ -162,22 +166,7 @@ func NewExecuteQueryLambdaCmd() *cobra.Command {
 				return err
 			}
 
-			var opts []option.QueryLambdaOption
-			if version, _ := cmd.Flags().GetString(flag.Version); version != "" {
-				logger.Info("executing ql with", "version", version)
-				opts = []option.QueryLambdaOption{option.WithVersion(version)}
-			}
-			if tag, _ := cmd.Flags().GetString("tag"); tag != "" {
-				opts = []option.QueryLambdaOption{option.WithTag(tag)}
-				logger.Info("executing ql with", "tag", tag)
-			}
-
-			params, _ := cmd.Flags().GetStringArray("param")
 			paramFile, _ := cmd.Flags().GetString("params-file")
-			if len(params) > 0 && paramFile != "" {
-
-			}
-
 			if paramFile != "" {
 				f, err := os.Open(paramFile)
 				if err != nil {
@@ -185,11 +174,11 @@ func NewExecuteQueryLambdaCmd() *cobra.Command {
 				}
 				_ = f
 				panic("not implemented - need to define file format")
-			} else {
-				for _, p := range params {
-					fields := strings.SplitN(p, ":", 2)
-					opts = append(opts, option.WithQueryLambdaParameter(fields[0], "", fields[1]))
-				}
+			}
+
+			opts, err := queryLambdaOptions(cmd)
+			if err != nil {
+				return err
 			}
 
 			resp, err := rs.ExecuteQueryLambda(ctx, ws, args[0], opts...)
@@ -206,6 +195,7 @@ func NewExecuteQueryLambdaCmd() *cobra.Command {
 
 	cmd.Flags().String(flag.Version, "", "query lambda version")
 	cmd.Flags().String("tag", "", "query lambda tag")
+	cmd.MarkFlagsMutuallyExclusive(flag.Version, "tag")
 	cmd.Flags().StringP("params-file", "P", "", "query parameters file")
 	cmd.Flags().StringArrayP("param", "p", nil, "query parameters")
 	_ = cobra.MarkFlagFilename(cmd.Flags(), "params", ".json")
@@ -213,6 +203,28 @@ func NewExecuteQueryLambdaCmd() *cobra.Command {
 	return &cmd
 }
 
+// queryLambdaOptions returns the version, tag and parameter options for executing a query lambda
+func queryLambdaOptions(cmd *cobra.Command) ([]option.QueryLambdaOption, error) {
+	var opts []option.QueryLambdaOption
+	if version, _ := cmd.Flags().GetString(flag.Version); version != "" {
+		opts = append(opts, option.WithVersion(version))
+	}
+	if tag, _ := cmd.Flags().GetString(flag.Tag); tag != "" {
+		opts = append(opts, option.WithTag(tag))
+	}
+
+	params, _ := cmd.Flags().GetStringArray(flag.Param)
+	for _, p := range params {
+		name, value, found := strings.Cut(p, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid parameter %s, must be NAME:VALUE", p)
+		}
+		opts = append(opts, option.WithQueryLambdaParameter(name, "", value))
+	}
+
+	return opts, nil
+}
+
 func newCreateQueryLambdaCmd() *cobra.Command {
 	cmd := cobra.Command{
 		Use:         "lambda NAME",
@@ -259,7 +271,7 @@ func newCreateQueryLambdaCmd() *cobra.Command {
 	_ = cmd.RegisterFlagCompletionFunc(flag.Workspace, completion.Workspace(Version))
 
 	cmd.Flags().String(flag.Description, "", "description of the query lambda")
-	cmd.Flags().Bool(flag.Wait, false, "wait until query lambda is ready")
+	addWaitFlags(&cmd, "query lambda is active")
 
 	cmd.Flags().String(flag.SQL, "", "file containing SQL")
 	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.SQL)
@@ -350,7 +362,7 @@ func newUpdateQueryLambdaCmd() *cobra.Command {
 	_ = cmd.RegisterFlagCompletionFunc(flag.Workspace, completion.Workspace(Version))
 
 	cmd.Flags().String(flag.Description, "", "description of the query lambda")
-	cmd.Flags().Bool(flag.Wait, false, "wait until query lambda is ready")
+	addWaitFlags(&cmd, "query lambda is active")
 
 	cmd.Flags().String(flag.SQL, "", "file containing SQL")
 	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.SQL)
@@ -360,16 +372,108 @@ func newUpdateQueryLambdaCmd() *cobra.Command {
 }
 
 func waitUntilQLActive(rs *rockset.RockClient, cmd *cobra.Command, ws, name, version string) error {
-	wait, err := cmd.Flags().GetBool(flag.Wait)
+	return waitFor(cmd.Context(), cmd, wait.Target{
+		Resource: fmt.Sprintf("query lambda %s.%s:%s", ws, name, version),
+		Ready:    []string{option.QueryLambdaActive.String()},
+		Failed:   []string{option.QueryLambdaInvalidSQL.String()},
+		State: func(ctx context.Context) (string, error) {
+			ql, err := rs.GetQueryLambdaVersion(ctx, ws, name, version)
+			return ql.GetState(), err
+		},
+	})
+}
+
+func newDiffQueryLambdaCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:     "lambda NAME VERSION|TAG VERSION|TAG",
+		Aliases: []string{"ql"},
+		Short:   "diff two query lambda versions",
+		Long: `show the differences in SQL and default parameters between two versions of a query lambda,
+each version can be specified either as a version or a tag`,
+		Example: `	## compare the version tagged as production with the latest version
+	rockset diff lambda --workspace commons mylambda production latest`,
+		Args:              cobra.ExactArgs(3),
+		Annotations:       group("lambda"),
+		ValidArgsFunction: completion.Lambda(Version),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ws, _ := cmd.Flags().GetString(flag.Workspace)
+			name := args[0]
+
+			ctx := cmd.Context()
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			from, err := getQueryLambdaVersion(ctx, rs, ws, name, args[1])
+			if err != nil {
+				return err
+			}
+
+			to, err := getQueryLambdaVersion(ctx, rs, ws, name, args[2])
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			_, _ = fmt.Fprintf(out, "--- %s.%s:%s\n+++ %s.%s:%s\n", ws, name, from.GetVersion(), ws, name, to.GetVersion())
+
+			fromSQL, toSQL := from.GetSql(), to.GetSql()
+			sqlDiff := diff.Text(fromSQL.GetQuery(), toSQL.GetQuery())
+			paramDiff := diffQueryParameters(fromSQL.GetDefaultParameters(), toSQL.GetDefaultParameters())
+
+			if !diff.Changed(sqlDiff) && !diff.Changed(paramDiff) {
+				_, _ = fmt.Fprintf(out, "no differences\n")
+				return nil
+			}
+
+			_, _ = fmt.Fprintf(out, "\nSQL:\n")
+			diff.Write(out, sqlDiff)
+
+			_, _ = fmt.Fprintf(out, "\nDefault parameters:\n")
+			if diff.Changed(paramDiff) {
+				diff.Write(out, paramDiff)
+			} else {
+				_, _ = fmt.Fprintf(out, "  no differences\n")
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP(flag.Workspace, flag.WorkspaceShort, flag.DefaultWorkspace, "workspace of the query lambda")
+	_ = cmd.RegisterFlagCompletionFunc(flag.Workspace, completion.Workspace(Version))
+
+	return &cmd
+}
+
+// getQueryLambdaVersion gets a query lambda version using either a tag or a version
+func getQueryLambdaVersion(ctx context.Context, rs *rockset.RockClient, ws, name, versionOrTag string) (openapi.QueryLambdaVersion, error) {
+	tags, err := rs.ListQueryLambdaTags(ctx, ws, name)
 	if err != nil {
-		return err
+		return openapi.QueryLambdaVersion{}, err
+	}
+
+	for _, t := range tags {
+		if t.GetTagName() == versionOrTag {
+			return t.GetVersion(), nil
+		}
 	}
-	if wait {
-		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "waiting until query lambda is ready...")
-		if err := rs.Wait.UntilQueryLambdaVersionActive(cmd.Context(), ws, name, version); err != nil {
-			return fmt.Errorf("failed to wait for %s.%s:%s to be active: %v", ws, name, version, err)
+
+	return rs.GetQueryLambdaVersion(ctx, ws, name, versionOrTag)
+}
+
+// diffQueryParameters diffs the default parameters, one line per parameter sorted by name
+func diffQueryParameters(from, to []openapi.QueryParameter) []diff.Line {
+	asLines := func(params []openapi.QueryParameter) []string {
+		lines := make([]string, len(params))
+		for i, p := range params {
+			lines[i] = fmt.Sprintf("%s (%s) = %s", p.Name, p.Type, p.Value)
 		}
+		gosort.Strings(lines)
+
+		return lines
 	}
 
-	return nil
+	return diff.Lines(asLines(from), asLines(to))
 }
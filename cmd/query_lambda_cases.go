@@ -0,0 +1,460 @@
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	gosort "sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rockset/rockset-go-client/option"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/rockset/cli/completion"
+	"github.com/rockset/cli/config"
+	"github.com/rockset/cli/diff"
+	"github.com/rockset/cli/flag"
+	"github.com/rockset/cli/tui"
+)
+
+func newTestQueryLambdaCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:     "lambda NAME",
+		Aliases: []string{"ql"},
+		Short:   "test query lambda",
+		Long: `execute a query lambda once for each test case in the cases file, and compare the result
+with the expected rows, row count or predicate`,
+		Example: `	## run the test cases against the version tagged as latest, and save a JUnit report
+	rockset test lambda --cases cases.yaml --tag latest --junit report.xml mylambda
+
+	## example cases file
+	cases:
+	  - name: movies from 2000
+	    parameters:
+	      year: "2000"
+	    expect:
+	      count: 3
+	  - name: ratings are in range
+	    expect:
+	      predicate: rating <= 10
+	  - name: exact result
+	    tag: production
+	    expect:
+	      rows:
+	        - title: Gladiator
+	          year: 2000`,
+		Args:              cobra.ExactArgs(1),
+		Annotations:       group("lambda"),
+		ValidArgsFunction: completion.Lambda(Version),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			ws, _ := cmd.Flags().GetString(flag.Workspace)
+			casesFile, _ := cmd.Flags().GetString(flag.Cases)
+			junitFile, _ := cmd.Flags().GetString(flag.JUnit)
+			version, _ := cmd.Flags().GetString(flag.Version)
+			tag, _ := cmd.Flags().GetString(flag.Tag)
+			name := args[0]
+
+			suite, err := LoadLambdaTestSuite(casesFile)
+			if err != nil {
+				return err
+			}
+
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			results := make([]LambdaTestResult, len(suite.Cases))
+			var failed int
+			for i, tc := range suite.Cases {
+				opts := tc.options(version, tag)
+
+				start := time.Now()
+				resp, err := rs.ExecuteQueryLambda(ctx, ws, name, opts...)
+				elapsed := time.Since(start)
+
+				switch {
+				case err != nil:
+					results[i] = LambdaTestResult{Name: tc.Name, Failures: []string{err.Error()}}
+				case resp.GetStatus() == "ERROR":
+					results[i] = LambdaTestResult{Name: tc.Name, Failures: []string{queryResponseError(resp).Error()}}
+				case resp.GetStatus() != "COMPLETED":
+					results[i] = LambdaTestResult{Name: tc.Name,
+						Failures: []string{fmt.Sprintf("unexpected query status: %s", resp.GetStatus())}}
+				default:
+					results[i] = tc.Check(resp.Results)
+				}
+				results[i].Elapsed = elapsed
+
+				if !results[i].Passed() {
+					failed++
+				}
+				showLambdaTestResult(out, results[i])
+			}
+
+			_, _ = fmt.Fprintf(out, "\n%d passed, %d failed\n", len(results)-failed, failed)
+
+			if junitFile != "" {
+				if err = writeJUnitFile(junitFile, fmt.Sprintf("%s.%s", ws, name), results); err != nil {
+					return err
+				}
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("%d of %d test cases failed", failed, len(results))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP(flag.Workspace, flag.WorkspaceShort, flag.DefaultWorkspace, "workspace name")
+	_ = cmd.RegisterFlagCompletionFunc(flag.Workspace, completion.Workspace(Version))
+
+	cmd.Flags().String(flag.Cases, "", "file containing the test cases")
+	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.Cases)
+	_ = cobra.MarkFlagFilename(cmd.Flags(), flag.Cases, ".yaml", ".yml")
+
+	cmd.Flags().String(flag.JUnit, "", "write a JUnit XML report to this file")
+	_ = cobra.MarkFlagFilename(cmd.Flags(), flag.JUnit, ".xml")
+
+	cmd.Flags().String(flag.Version, "", "query lambda version, unless overridden by the test case")
+	_ = cmd.RegisterFlagCompletionFunc(flag.Version, completion.LambdaVersion(Version))
+	cmd.Flags().String(flag.Tag, "", "query lambda tag, unless overridden by the test case")
+	_ = cmd.RegisterFlagCompletionFunc(flag.Tag, completion.LambdaTag(Version))
+	cmd.MarkFlagsMutuallyExclusive(flag.Version, flag.Tag)
+
+	return &cmd
+}
+
+// LambdaTestSuite is the file format used by the test lambda command
+type LambdaTestSuite struct {
+	Cases []LambdaTestCase `yaml:"cases"`
+}
+
+type LambdaTestCase struct {
+	Name string `yaml:"name"`
+	// Version and Tag override the version or tag given on the command line
+	Version    string            `yaml:"version,omitempty"`
+	Tag        string            `yaml:"tag,omitempty"`
+	Parameters map[string]string `yaml:"parameters,omitempty"`
+	Expect     LambdaExpectation `yaml:"expect"`
+}
+
+type LambdaExpectation struct {
+	// Rows are the exact rows expected, compared in order if Ordered is set
+	Rows    []map[string]any `yaml:"rows,omitempty"`
+	Ordered bool             `yaml:"ordered,omitempty"`
+	// Count is the expected number of rows
+	Count *int `yaml:"count,omitempty"`
+	// Predicate is a comparison which every row must satisfy, e.g. "year >= 2000"
+	Predicate string `yaml:"predicate,omitempty"`
+}
+
+type LambdaTestResult struct {
+	Name     string
+	Failures []string
+	Diff     []diff.Line
+	Elapsed  time.Duration
+}
+
+func (r LambdaTestResult) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+func LoadLambdaTestSuite(file string) (LambdaTestSuite, error) {
+	var suite LambdaTestSuite
+
+	f, err := os.Open(file)
+	if err != nil {
+		return suite, err
+	}
+	defer f.Close()
+
+	if err = yaml.NewDecoder(f).Decode(&suite); err != nil {
+		return suite, fmt.Errorf("failed to parse test cases in %s: %w", file, err)
+	}
+
+	if len(suite.Cases) == 0 {
+		return suite, fmt.Errorf("no test cases found in %s", file)
+	}
+
+	for i, tc := range suite.Cases {
+		if tc.Name == "" {
+			suite.Cases[i].Name = fmt.Sprintf("case %d", i+1)
+		}
+		if tc.Version != "" && tc.Tag != "" {
+			return suite, fmt.Errorf("test case %s can't have both a version and a tag", suite.Cases[i].Name)
+		}
+		if tc.Expect.Predicate != "" {
+			if _, err = ParsePredicate(tc.Expect.Predicate); err != nil {
+				return suite, fmt.Errorf("test case %s: %w", suite.Cases[i].Name, err)
+			}
+		}
+	}
+
+	return suite, nil
+}
+
+func (tc LambdaTestCase) options(version, tag string) []option.QueryLambdaOption {
+	var opts []option.QueryLambdaOption
+
+	switch {
+	case tc.Version != "":
+		opts = append(opts, option.WithVersion(tc.Version))
+	case tc.Tag != "":
+		opts = append(opts, option.WithTag(tc.Tag))
+	case version != "":
+		opts = append(opts, option.WithVersion(version))
+	case tag != "":
+		opts = append(opts, option.WithTag(tag))
+	}
+
+	for k, v := range tc.Parameters {
+		opts = append(opts, option.WithQueryLambdaParameter(k, "", v))
+	}
+
+	return opts
+}
+
+// Check compares the rows returned by the query lambda with the expectations of the test case
+func (tc LambdaTestCase) Check(rows []map[string]any) LambdaTestResult {
+	result := LambdaTestResult{Name: tc.Name}
+	e := tc.Expect
+
+	if e.Count != nil && *e.Count != len(rows) {
+		result.Failures = append(result.Failures, fmt.Sprintf("expected %d rows, got %d", *e.Count, len(rows)))
+	}
+
+	if e.Rows != nil {
+		expected, err := canonicalRows(e.Rows, e.Ordered)
+		if err != nil {
+			result.Failures = append(result.Failures, err.Error())
+			return result
+		}
+		actual, err := canonicalRows(rows, e.Ordered)
+		if err != nil {
+			result.Failures = append(result.Failures, err.Error())
+			return result
+		}
+
+		if lines := diff.Lines(expected, actual); diff.Changed(lines) {
+			result.Failures = append(result.Failures, "rows differ from the expected rows")
+			result.Diff = lines
+		}
+	}
+
+	if e.Predicate != "" {
+		p, err := ParsePredicate(e.Predicate)
+		if err != nil {
+			result.Failures = append(result.Failures, err.Error())
+			return result
+		}
+
+		for i, row := range rows {
+			if !p.match(row) {
+				result.Failures = append(result.Failures,
+					fmt.Sprintf("row %d doesn't satisfy %s: %s = %v", i, e.Predicate, p.Field, row[p.Field]))
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+// canonicalRows turns each row into its JSON representation, so values decoded from YAML and JSON can be compared
+func canonicalRows(rows []map[string]any, ordered bool) ([]string, error) {
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		// round trip through JSON to make all numbers float64
+		data, err := json.Marshal(row)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal row %d: %w", i, err)
+		}
+		var m map[string]any
+		if err = json.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal row %d: %w", i, err)
+		}
+		if data, err = json.Marshal(m); err != nil {
+			return nil, fmt.Errorf("failed to marshal row %d: %w", i, err)
+		}
+		lines[i] = string(data)
+	}
+
+	if !ordered {
+		gosort.Strings(lines)
+	}
+
+	return lines, nil
+}
+
+// Predicate is a comparison of a field of each row with a value
+type Predicate struct {
+	Field string
+	Op    string
+	Value string
+}
+
+var predicateOperators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// ParsePredicate parses FIELD OPERATOR VALUE, where the operator is the first one in the string, so the value
+// can contain operators too. At the same position the longest operator is used, so "<=" isn't parsed as "<".
+func ParsePredicate(s string) (Predicate, error) {
+	pos, op := -1, ""
+	for _, o := range predicateOperators {
+		i := strings.Index(s, o)
+		if i < 0 {
+			continue
+		}
+		if pos < 0 || i < pos || (i == pos && len(o) > len(op)) {
+			pos, op = i, o
+		}
+	}
+
+	if pos < 0 {
+		return Predicate{}, fmt.Errorf("invalid predicate '%s', must be FIELD OPERATOR VALUE where operator is one of %s",
+			s, strings.Join(predicateOperators, " "))
+	}
+
+	return Predicate{
+		Field: strings.TrimSpace(s[:pos]),
+		Op:    op,
+		Value: strings.Trim(strings.TrimSpace(s[pos+len(op):]), `"'`),
+	}, nil
+}
+
+func (p Predicate) match(row map[string]any) bool {
+	v, found := row[p.Field]
+	if !found {
+		return false
+	}
+
+	var cmp int
+	actual := fmt.Sprintf("%v", v)
+	a, aErr := strconv.ParseFloat(actual, 64)
+	b, bErr := strconv.ParseFloat(p.Value, 64)
+	if aErr == nil && bErr == nil {
+		switch {
+		case a < b:
+			cmp = -1
+		case a > b:
+			cmp = 1
+		}
+	} else {
+		cmp = strings.Compare(actual, p.Value)
+	}
+
+	switch p.Op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	default:
+		return false
+	}
+}
+
+func showLambdaTestResult(out io.Writer, r LambdaTestResult) {
+	if r.Passed() {
+		_, _ = fmt.Fprintf(out, "%s %s (%s)\n", tui.RocksetStyle.Render("PASS"), r.Name, r.Elapsed.Round(time.Millisecond))
+		return
+	}
+
+	_, _ = fmt.Fprintf(out, "%s %s (%s)\n", tui.ErrorStyle.Render("FAIL"), r.Name, r.Elapsed.Round(time.Millisecond))
+	for _, f := range r.Failures {
+		_, _ = fmt.Fprintf(out, "    %s\n", f)
+	}
+	if r.Diff != nil {
+		_, _ = fmt.Fprintf(out, "    --- expected\n    +++ actual\n")
+		for _, l := range r.Diff {
+			_, _ = fmt.Fprintf(out, "    %s %s\n", l.Op.Prefix(), l.Text)
+		}
+	}
+}
+
+type junitTestSuite struct {
+	XMLName  xml.Name        `xml:"testsuite"`
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the test results as a JUnit XML report
+func WriteJUnit(out io.Writer, name string, results []LambdaTestResult) error {
+	suite := junitTestSuite{Name: name, Tests: len(results)}
+
+	var total time.Duration
+	for _, r := range results {
+		total += r.Elapsed
+		tc := junitTestCase{
+			Name:      r.Name,
+			ClassName: name,
+			Time:      fmt.Sprintf("%.3f", r.Elapsed.Seconds()),
+		}
+
+		if !r.Passed() {
+			suite.Failures++
+			var b strings.Builder
+			for _, f := range r.Failures {
+				b.WriteString(f + "\n")
+			}
+			diff.Write(&b, r.Diff)
+			tc.Failure = &junitFailure{Message: r.Failures[0], Text: b.String()}
+		}
+
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Time = fmt.Sprintf("%.3f", total.Seconds())
+
+	if _, err := io.WriteString(out, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(out)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suite); err != nil {
+		return err
+	}
+	_, err := io.WriteString(out, "\n")
+
+	return err
+}
+
+func writeJUnitFile(file, name string, results []LambdaTestResult) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	return errors.Join(WriteJUnit(f, name, results), f.Close())
+}
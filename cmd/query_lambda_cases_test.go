@@ -0,0 +1,82 @@
+package cmd_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/cli/cmd"
+)
+
+func TestLoadLambdaTestSuite(t *testing.T) {
+	suite, err := cmd.LoadLambdaTestSuite("testdata/lambda_cases.yaml")
+	require.NoError(t, err)
+
+	require.Len(t, suite.Cases, 3)
+	assert.Equal(t, "count", suite.Cases[0].Name)
+	assert.Equal(t, "2000", suite.Cases[0].Parameters["year"])
+	assert.Equal(t, "production", suite.Cases[1].Tag)
+	assert.Equal(t, "case 3", suite.Cases[2].Name)
+}
+
+func TestLambdaTestCase_Check(t *testing.T) {
+	suite, err := cmd.LoadLambdaTestSuite("testdata/lambda_cases.yaml")
+	require.NoError(t, err)
+
+	// rows as they are decoded from a JSON query response
+	rows := []map[string]any{
+		{"title": "Memento", "year": float64(2000)},
+		{"title": "Gladiator", "year": float64(2000)},
+	}
+
+	for _, tc := range suite.Cases {
+		result := tc.Check(rows)
+		assert.True(t, result.Passed(), "%s: %v", tc.Name, result.Failures)
+	}
+
+	rows = append(rows, map[string]any{"title": "Heat", "year": float64(1995)})
+	for _, tc := range suite.Cases {
+		result := tc.Check(rows)
+		assert.False(t, result.Passed(), tc.Name)
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var out bytes.Buffer
+	err := cmd.WriteJUnit(&out, "commons.test", []cmd.LambdaTestResult{
+		{Name: "ok"},
+		{Name: "bad", Failures: []string{"expected 1 rows, got 2"}},
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, out.String(), `<testsuite name="commons.test" tests="2" failures="1"`)
+	assert.Contains(t, out.String(), `<failure message="expected 1 rows, got 2">`)
+}
+
+func TestParsePredicate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want cmd.Predicate
+	}{
+		{"year >= 2000", cmd.Predicate{Field: "year", Op: ">=", Value: "2000"}},
+		{"year<2000", cmd.Predicate{Field: "year", Op: "<", Value: "2000"}},
+		{`title == "Memento"`, cmd.Predicate{Field: "title", Op: "==", Value: "Memento"}},
+		{"status==a!=b", cmd.Predicate{Field: "status", Op: "==", Value: "a!=b"}},
+		{"status!=a==b", cmd.Predicate{Field: "status", Op: "!=", Value: "a==b"}},
+		{"x<=y>1", cmd.Predicate{Field: "x", Op: "<=", Value: "y>1"}},
+		{"x>1<=y", cmd.Predicate{Field: "x", Op: ">", Value: "1<=y"}},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.in, func(t *testing.T) {
+			p, err := cmd.ParsePredicate(tst.in)
+			require.NoError(t, err)
+			assert.Equal(t, tst.want, p)
+		})
+	}
+
+	_, err := cmd.ParsePredicate("year 2000")
+	assert.ErrorContains(t, err, "invalid predicate")
+}
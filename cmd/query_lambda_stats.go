@@ -0,0 +1,266 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rockset/rockset-go-client/openapi"
+	"github.com/rockset/rockset-go-client/option"
+	"github.com/spf13/cobra"
+
+	"github.com/rockset/cli/completion"
+	"github.com/rockset/cli/config"
+	"github.com/rockset/cli/flag"
+	"github.com/rockset/cli/format"
+	"github.com/rockset/cli/sort"
+)
+
+const (
+	sortByName         = "name"
+	sortByLastExecuted = "last-executed"
+	sortByVersions     = "versions"
+	sortByStale        = "stale"
+)
+
+var lambdaStatsSortKeys = []string{sortByName, sortByLastExecuted, sortByVersions, sortByStale}
+
+// versionStatsSortKeys are the sort keys which can be used with --versions
+var versionStatsSortKeys = []string{sortByName, sortByLastExecuted, sortByStale}
+
+func newStatsQueryLambdasCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:     "lambdas",
+		Aliases: []string{"ql", "qls", "querylambdas"},
+		Args:    cobra.NoArgs,
+		Short:   "show query lambda usage statistics",
+		Long: `show when query lambdas and their versions were last executed or last failed,
+and which versions never have been executed.
+
+The Rockset API only keeps track of the last execution and the last failure of each version,
+so the counts are the number of versions which have been executed, never been executed,
+or failed the last time they were executed.`,
+		Example: `	## find query lambdas which haven't been used in the last 30 days
+	rockset stats lambdas --unused-since 30d
+
+	## show all versions, least recently executed first
+	rockset stats lambdas --versions --sort-by last-executed`,
+		Annotations: group("lambda"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			ws, _ := cmd.Flags().GetString(flag.Workspace)
+			versions, _ := cmd.Flags().GetBool(flag.Versions)
+			sortBy, _ := cmd.Flags().GetString(flag.SortBy)
+			unused, _ := cmd.Flags().GetString(flag.UnusedSince)
+
+			var cutoff time.Time
+			if unused != "" {
+				age, err := parseAge(unused)
+				if err != nil {
+					return err
+				}
+				cutoff = time.Now().Add(-age)
+			}
+
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			var opts []option.ListQueryLambdaOption
+			if ws != "" && ws != flag.AllWorkspaces {
+				opts = append(opts, option.WithQueryLambdaWorkspace(ws))
+			}
+
+			lambdas, err := rs.ListQueryLambdas(ctx, opts...)
+			if err != nil {
+				return err
+			}
+
+			var lambdaUsage []format.QueryLambdaUsage
+			var versionUsage []format.QueryLambdaVersionUsage
+			for _, ql := range lambdas {
+				list, err := rs.ListQueryLambdaVersions(ctx, ql.GetWorkspace(), ql.GetName())
+				if err != nil {
+					return err
+				}
+
+				var tags []openapi.QueryLambdaTag
+				if versions {
+					if tags, err = rs.ListQueryLambdaTags(ctx, ql.GetWorkspace(), ql.GetName()); err != nil {
+						return err
+					}
+				}
+
+				usage := format.QueryLambdaUsage{Workspace: ql.GetWorkspace(), Name: ql.GetName()}
+				for _, v := range list {
+					vu := queryLambdaVersionUsage(v, tags)
+					addVersionUsage(&usage, vu)
+
+					if versions && (cutoff.IsZero() || vu.LastExecuted < cutoff.UnixMilli()) {
+						versionUsage = append(versionUsage, vu)
+					}
+				}
+
+				if cutoff.IsZero() || usage.LastExecuted < cutoff.UnixMilli() {
+					lambdaUsage = append(lambdaUsage, usage)
+				}
+			}
+
+			if versions {
+				less, err := versionUsageLessFunc(sortBy)
+				if err != nil {
+					return err
+				}
+				ms := sort.Multi[format.QueryLambdaVersionUsage]{
+					LessFuncs: []func(p1 *format.QueryLambdaVersionUsage, p2 *format.QueryLambdaVersionUsage) bool{
+						less,
+						sort.ByWorkspace[*format.QueryLambdaVersionUsage],
+						sort.ByName[*format.QueryLambdaVersionUsage],
+					},
+				}
+				ms.Sort(versionUsage)
+
+				return formatList(cmd, format.ToInterfaceArray(versionUsage))
+			}
+
+			less, err := usageLessFunc(sortBy)
+			if err != nil {
+				return err
+			}
+			ms := sort.Multi[format.QueryLambdaUsage]{
+				LessFuncs: []func(p1 *format.QueryLambdaUsage, p2 *format.QueryLambdaUsage) bool{
+					less,
+					sort.ByWorkspace[*format.QueryLambdaUsage],
+					sort.ByName[*format.QueryLambdaUsage],
+				},
+			}
+			ms.Sort(lambdaUsage)
+
+			return formatList(cmd, format.ToInterfaceArray(lambdaUsage))
+		},
+	}
+
+	cmd.Flags().StringP(flag.Workspace, flag.WorkspaceShort, flag.AllWorkspaces, "only show query lambdas for the selected workspace")
+	_ = cmd.RegisterFlagCompletionFunc(flag.Workspace, completion.Workspace(Version))
+
+	cmd.Flags().Bool(flag.Versions, false, "show statistics for each query lambda version")
+	cmd.Flags().Bool(flag.Wide, false, "display more information")
+	cmd.Flags().String(flag.UnusedSince, "",
+		"only show query lambdas which haven't been executed within this duration, e.g. 30d or 12h")
+
+	cmd.Flags().String(flag.SortBy, sortByName, fmt.Sprintf("sort by one of: %s", strings.Join(lambdaStatsSortKeys, ", ")))
+	_ = cmd.RegisterFlagCompletionFunc(flag.SortBy,
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return lambdaStatsSortKeys, cobra.ShellCompDirectiveNoFileComp
+		})
+
+	return &cmd
+}
+
+func queryLambdaVersionUsage(v openapi.QueryLambdaVersion, tags []openapi.QueryLambdaTag) format.QueryLambdaVersionUsage {
+	stats := v.GetStats()
+	vu := format.QueryLambdaVersionUsage{
+		Workspace:        v.GetWorkspace(),
+		Name:             v.GetName(),
+		Version:          v.GetVersion(),
+		State:            v.GetState(),
+		LastExecuted:     parseISO8601Millis(stats.GetLastExecuted()),
+		LastExecutedBy:   stats.GetLastExecutedBy(),
+		LastError:        parseISO8601Millis(stats.GetLastExecutionError()),
+		LastErrorMessage: stats.GetLastExecutionErrorMessage(),
+	}
+	vu.Stale = vu.LastExecuted == 0
+	// the last execution failed if the last error is at least as recent as the last execution
+	vu.Failing = vu.LastError != 0 && vu.LastError >= vu.LastExecuted
+
+	for _, t := range tags {
+		tv := t.GetVersion()
+		if tv.GetVersion() == vu.Version {
+			vu.Tags = append(vu.Tags, t.GetTagName())
+		}
+	}
+
+	return vu
+}
+
+// addVersionUsage adds the statistics of a query lambda version to the query lambda summary
+func addVersionUsage(u *format.QueryLambdaUsage, vu format.QueryLambdaVersionUsage) {
+	u.Versions++
+	if vu.Stale {
+		u.StaleVersions++
+	} else {
+		u.ExecutedVersions++
+	}
+	if vu.Failing {
+		u.FailingVersions++
+	}
+
+	if vu.LastExecuted > u.LastExecuted {
+		u.LastExecuted = vu.LastExecuted
+		u.LastExecutedBy = vu.LastExecutedBy
+	}
+	if vu.LastError > u.LastError {
+		u.LastError = vu.LastError
+		u.LastErrorMessage = vu.LastErrorMessage
+	}
+}
+
+func usageLessFunc(sortBy string) (func(p1, p2 *format.QueryLambdaUsage) bool, error) {
+	switch sortBy {
+	case sortByName:
+		return sort.ByName[*format.QueryLambdaUsage], nil
+	case sortByLastExecuted:
+		return func(p1, p2 *format.QueryLambdaUsage) bool { return p1.LastExecuted < p2.LastExecuted }, nil
+	case sortByVersions:
+		return func(p1, p2 *format.QueryLambdaUsage) bool { return p1.Versions > p2.Versions }, nil
+	case sortByStale:
+		return func(p1, p2 *format.QueryLambdaUsage) bool { return p1.StaleVersions > p2.StaleVersions }, nil
+	default:
+		return nil, fmt.Errorf("can't sort by %s, valid options are: %s", sortBy, strings.Join(lambdaStatsSortKeys, ", "))
+	}
+}
+
+func versionUsageLessFunc(sortBy string) (func(p1, p2 *format.QueryLambdaVersionUsage) bool, error) {
+	switch sortBy {
+	case sortByName:
+		return sort.ByName[*format.QueryLambdaVersionUsage], nil
+	case sortByLastExecuted:
+		return func(p1, p2 *format.QueryLambdaVersionUsage) bool { return p1.LastExecuted < p2.LastExecuted }, nil
+	case sortByStale:
+		return func(p1, p2 *format.QueryLambdaVersionUsage) bool { return p1.Stale && !p2.Stale }, nil
+	default:
+		return nil, fmt.Errorf("can't sort by %s with --%s, valid options are: %s", sortBy, flag.Versions,
+			strings.Join(versionStatsSortKeys, ", "))
+	}
+}
+
+// parseISO8601Millis parses an ISO-8601 date from the API, and returns it as unix milliseconds, or 0 if it is missing
+func parseISO8601Millis(s string) int64 {
+	if s == "" {
+		return 0
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		logger.Debug("failed to parse time", "time", s, "err", err)
+		return 0
+	}
+
+	return t.UnixMilli()
+}
+
+// parseAge parses a duration like time.ParseDuration, but also accepts days, e.g. 30d
+func parseAge(s string) (time.Duration, error) {
+	if days, found := strings.CutSuffix(s, "d"); found {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number of days %s: %w", s, err)
+		}
+
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	return time.ParseDuration(s)
+}
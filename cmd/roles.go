@@ -1,12 +1,19 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/rockset/rockset-go-client"
 	"github.com/rockset/rockset-go-client/openapi"
 	"github.com/spf13/cobra"
 
 	"github.com/rockset/cli/completion"
 	"github.com/rockset/cli/config"
+	"github.com/rockset/cli/flag"
 	"github.com/rockset/cli/format"
+	"github.com/rockset/cli/role"
 	"github.com/rockset/cli/sort"
 )
 
@@ -63,9 +70,332 @@ func newGetRoleCommand() *cobra.Command {
 				return err
 			}
 
+			if definition, _ := cmd.Flags().GetBool(flag.Definition); definition {
+				return role.Write(cmd.OutOrStdout(), role.FromAPI(alias))
+			}
+
 			return formatOne(cmd, alias)
 		},
 	}
 
+	cmd.Flags().Bool(flag.Definition, false, "show the YAML role definition, which can be used with update role -f")
+
+	return &cmd
+}
+
+func newCreateRoleCommand() *cobra.Command {
+	cmd := cobra.Command{
+		Use:     "role [NAME]",
+		Aliases: []string{"r"},
+		Args:    cobra.MaximumNArgs(1),
+		Short:   "create role",
+		Long: `create a role, either empty to grant privileges to later, or from a YAML role definition.
+
+A role definition has a name, an optional description, and a list of privileges, where each privilege has an
+action, a resource unless it is a global action, and for workspace and virtual instance actions an optional cluster:
+
+	name: reader
+	description: read only access to commons
+	privileges:
+	  - action: QUERY_DATA_WS
+	    resource: commons
+	  - action: LIST_WS_GLOBAL`,
+		Example: `	## create a role from a role definition
+	rockset create role -f reader.yaml
+
+	## create an empty role
+	rockset create role reader --description "read only access"`,
+		Annotations: group("role"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			def, err := roleDefinition(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			if _, err = rs.CreateRole(ctx, def.Name, def.Options()...); err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "role %s created with %s\n", def.Name,
+				plural(len(def.Privileges), "privilege"))
+
+			return nil
+		},
+	}
+
+	addRoleFlags(&cmd)
+
+	return &cmd
+}
+
+func newUpdateRoleCommand() *cobra.Command {
+	cmd := cobra.Command{
+		Use:     "role [NAME]",
+		Aliases: []string{"r"},
+		Args:    cobra.MaximumNArgs(1),
+		Short:   "update role",
+		Long: `update the description of a role, or replace the role with a YAML role definition,
+which shows the privileges which are added and removed.`,
+		Example: `	## show what changes when the role is updated from the role definition
+	rockset update role -f reader.yaml --dry-run`,
+		Annotations:       group("role"),
+		ValidArgsFunction: completion.Role(Version),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			dryRun, _ := cmd.Flags().GetBool(flag.DryRun)
+			out := cmd.OutOrStdout()
+
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			desired, err := roleDefinition(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			r, err := rs.GetRole(ctx, desired.Name)
+			if err != nil {
+				return err
+			}
+			current := role.FromAPI(r)
+
+			if file, _ := cmd.Flags().GetString(flag.File); file == "" {
+				// only the description is updated
+				desired.Privileges = current.Privileges
+			}
+
+			added, removed := current.Diff(desired)
+			if current.Description != desired.Description {
+				_, _ = fmt.Fprintf(out, "description: %q -> %q\n", current.Description, desired.Description)
+			}
+			for _, p := range added {
+				_, _ = fmt.Fprintf(out, "+ %s\n", p)
+			}
+			for _, p := range removed {
+				_, _ = fmt.Fprintf(out, "- %s\n", p)
+			}
+
+			if current.Description == desired.Description && len(added) == 0 && len(removed) == 0 {
+				_, _ = fmt.Fprintf(out, "role %s is up to date\n", desired.Name)
+				return nil
+			}
+			if dryRun {
+				return nil
+			}
+
+			if err = updateRole(ctx, rs, desired); err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(out, "role %s updated\n", desired.Name)
+
+			return nil
+		},
+	}
+
+	addRoleFlags(&cmd)
+	cmd.Flags().Bool(flag.DryRun, false, "only show what would change")
+
+	return &cmd
+}
+
+func newDeleteRoleCommand() *cobra.Command {
+	cmd := cobra.Command{
+		Use:               "role NAME",
+		Aliases:           []string{"r"},
+		Args:              cobra.ExactArgs(1),
+		Short:             "delete role",
+		Annotations:       group("role"),
+		ValidArgsFunction: completion.Role(Version),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			if ok, err := confirmDelete(cmd, []string{"role " + args[0]}); err != nil || !ok {
+				return err
+			}
+
+			if err = rs.DeleteRole(ctx, args[0]); err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "role %s deleted\n", args[0])
+
+			return nil
+		},
+	}
+
+	addDeleteFlags(&cmd)
+
 	return &cmd
 }
+
+func newGrantPrivilegeCommand() *cobra.Command {
+	cmd := cobra.Command{
+		Use:     "privilege ROLE ACTION [RESOURCE]",
+		Aliases: []string{"priv", "p"},
+		Args:    cobra.RangeArgs(2, 3),
+		Short:   "grant privilege to role",
+		Long: `grant a privilege to a role, where the resource is the workspace, virtual instance or integration
+the action is allowed on, and isn't used for global actions`,
+		Example: `	## allow the reader role to query the commons workspace
+	rockset grant privilege reader QUERY_DATA_WS commons
+
+	## allow the reader role to list workspaces
+	rockset grant privilege reader LIST_WS_GLOBAL`,
+		Annotations:       group("role"),
+		ValidArgsFunction: privilegeCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return changePrivilege(cmd, args, true)
+		},
+	}
+
+	addPrivilegeFlags(&cmd)
+
+	return &cmd
+}
+
+func newRevokePrivilegeCommand() *cobra.Command {
+	cmd := cobra.Command{
+		Use:     "privilege ROLE ACTION [RESOURCE]",
+		Aliases: []string{"priv", "p"},
+		Args:    cobra.RangeArgs(2, 3),
+		Short:   "revoke privilege from role",
+		Example: `	## stop the reader role from querying the commons workspace
+	rockset revoke privilege reader QUERY_DATA_WS commons`,
+		Annotations:       group("role"),
+		ValidArgsFunction: privilegeCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return changePrivilege(cmd, args, false)
+		},
+	}
+
+	addPrivilegeFlags(&cmd)
+
+	return &cmd
+}
+
+func addRoleFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP(flag.File, "f", "", "YAML file with the role definition")
+	_ = cobra.MarkFlagFilename(cmd.Flags(), flag.File, ".yaml", ".yml")
+	cmd.Flags().String(flag.Description, "", "description of the role, overrides the role definition")
+}
+
+func addPrivilegeFlags(cmd *cobra.Command) {
+	cmd.Flags().String(flag.PrivilegeCluster, "",
+		"cluster the workspace or virtual instance action is allowed in, defaults to all clusters")
+}
+
+// roleDefinition returns the role definition from the file, or an empty role if no file is used,
+// where the name and description can be overridden using the argument and flag
+func roleDefinition(cmd *cobra.Command, args []string) (role.Definition, error) {
+	var def role.Definition
+
+	if file, _ := cmd.Flags().GetString(flag.File); file != "" {
+		var err error
+		if def, err = role.Load(file); err != nil {
+			return role.Definition{}, err
+		}
+	}
+
+	if len(args) > 0 {
+		def.Name = args[0]
+	}
+	if cmd.Flags().Changed(flag.Description) {
+		def.Description, _ = cmd.Flags().GetString(flag.Description)
+	}
+
+	if def.Name == "" {
+		return role.Definition{}, fmt.Errorf("the role name must be given as argument or in the role definition")
+	}
+
+	return def, nil
+}
+
+// changePrivilege grants or revokes the privilege in the arguments
+func changePrivilege(cmd *cobra.Command, args []string, grant bool) error {
+	ctx := cmd.Context()
+	p := role.Privilege{Action: args[1]}
+	if len(args) > 2 {
+		p.Resource = args[2]
+	}
+	p.Cluster, _ = cmd.Flags().GetString(flag.PrivilegeCluster)
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	rs, err := config.Client(cmd, Version)
+	if err != nil {
+		return err
+	}
+
+	r, err := rs.GetRole(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	def := role.FromAPI(r)
+
+	out := cmd.OutOrStdout()
+	if grant {
+		if def.Has(p) {
+			_, _ = fmt.Fprintf(out, "role %s already has %s\n", def.Name, p)
+			return nil
+		}
+		def.Privileges = append(def.Privileges, p)
+	} else {
+		if !def.Has(p) {
+			_, _ = fmt.Fprintf(out, "role %s doesn't have %s\n", def.Name, p)
+			return nil
+		}
+		def.Privileges = slices.DeleteFunc(def.Privileges, func(o role.Privilege) bool {
+			return o.Normalize() == p.Normalize()
+		})
+	}
+
+	if err = updateRole(ctx, rs, def); err != nil {
+		return err
+	}
+
+	if grant {
+		_, _ = fmt.Fprintf(out, "granted %s to role %s\n", p, def.Name)
+	} else {
+		_, _ = fmt.Fprintf(out, "revoked %s from role %s\n", p, def.Name)
+	}
+
+	return nil
+}
+
+// updateRole replaces the description and privileges of the role
+func updateRole(ctx context.Context, rs *rockset.RockClient, def role.Definition) error {
+	// the API ignores an empty list of privileges, so they can't all be removed
+	if len(def.Privileges) == 0 {
+		return fmt.Errorf("role %s must have at least one privilege, delete the role instead", def.Name)
+	}
+
+	_, err := rs.UpdateRole(ctx, def.Name, def.Options()...)
+
+	return err
+}
+
+func privilegeCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return completion.Role(Version)(cmd, args, toComplete)
+	case 1:
+		return role.Actions(), cobra.ShellCompDirectiveNoFileComp
+	default:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
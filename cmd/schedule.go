@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/rockset/rockset-go-client"
+	"github.com/rockset/rockset-go-client/option"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/rockset/cli/completion"
+	"github.com/rockset/cli/config"
+	"github.com/rockset/cli/flag"
+	"github.com/rockset/cli/lookup"
+	"github.com/rockset/cli/schedule"
+)
+
+func newScheduleVirtualInstanceCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:     "virtualinstance NAME",
+		Aliases: []string{"vi"},
+		Short:   "schedule virtual instance suspend, resume and resize",
+		Long: `set, show or remove the schedule for a virtual instance.
+The schedules are stored locally and applied by 'rockset scheduler run'.
+
+Each rule has an action (suspend, resume or resize), a time of day, and optionally the days it applies to
+(sun, mon, tue, wed, thu, fri, sat, weekdays, weekends or daily) and the size to resize to.`,
+		Example: `	## suspend the dev virtual instance at night and resize it during peak hours
+	rockset schedule vi dev --file schedule.yaml
+
+	## schedule.yaml
+	timezone: America/Los_Angeles
+	rules:
+	  - action: suspend
+	    at: "20:00"
+	    days: [weekdays]
+	  - action: resume
+	    at: "07:00"
+	    days: [weekdays]
+	  - action: resize
+	    at: "09:00"
+	    days: [weekdays]
+	    size: LARGE
+	  - action: resize
+	    at: "17:00"
+	    days: [weekdays]
+	    size: SMALL
+
+	## show the current schedule
+	rockset schedule vi dev`,
+		Args:              cobra.ExactArgs(1),
+		Annotations:       group("virtual instance"),
+		ValidArgsFunction: completion.VirtualInstance(Version),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, _ := cmd.Flags().GetString(flag.File)
+			remove, _ := cmd.Flags().GetBool(flag.Remove)
+			name := args[0]
+
+			scheduleFile, err := config.ScheduleFile()
+			if err != nil {
+				return err
+			}
+
+			schedules, err := schedule.Load(scheduleFile)
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case remove:
+				if _, found := schedules[name]; !found {
+					return fmt.Errorf("no schedule found for %s", name)
+				}
+				delete(schedules, name)
+				if err = schedule.Store(scheduleFile, schedules); err != nil {
+					return err
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "schedule for virtual instance '%s' removed\n", name)
+			case file != "":
+				data, err := os.ReadFile(file)
+				if err != nil {
+					return err
+				}
+				s, err := schedule.Parse(data)
+				if err != nil {
+					return fmt.Errorf("invalid schedule in %s: %w", file, err)
+				}
+				for i, r := range s.Rules {
+					if r.Action != schedule.Resize {
+						continue
+					}
+					size, err := ParseVirtualInstanceSize(r.Size)
+					if err != nil {
+						return fmt.Errorf("invalid schedule in %s: rule %d: %w", file, i+1, err)
+					}
+					s.Rules[i].Size = size.String()
+				}
+				schedules[name] = s
+				if err = schedule.Store(scheduleFile, schedules); err != nil {
+					return err
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "schedule for virtual instance '%s' saved\n", name)
+			default:
+				s, found := schedules[name]
+				if !found {
+					return fmt.Errorf("no schedule found for %s", name)
+				}
+				return yaml.NewEncoder(cmd.OutOrStdout()).Encode(s)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP(flag.File, "f", "", "YAML file with the schedule")
+	_ = cobra.MarkFlagFilename(cmd.Flags(), flag.File, ".yaml", ".yml")
+	cmd.Flags().Bool(flag.Remove, false, "remove the schedule")
+	cmd.MarkFlagsMutuallyExclusive(flag.File, flag.Remove)
+
+	return &cmd
+}
+
+func newSchedulerRunCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "run",
+		Args:  cobra.NoArgs,
+		Short: "run the virtual instance scheduler",
+		Long: `run the scheduler until interrupted, suspending, resuming and resizing virtual instances
+according to the schedules set with 'rockset schedule vi'.
+The schedules are reloaded every interval, so they can be changed while the scheduler runs.`,
+		Example: `	## show what the scheduler will do during the next 48 hours
+	rockset scheduler run --dry-run --horizon 48h`,
+		Annotations: group("virtual instance"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			dryRun, _ := cmd.Flags().GetBool(flag.DryRun)
+			horizon, _ := cmd.Flags().GetDuration(flag.Horizon)
+			interval, _ := cmd.Flags().GetDuration(flag.Interval)
+			if interval <= 0 {
+				return fmt.Errorf("--%s must be positive", flag.Interval)
+			}
+			out := cmd.OutOrStdout()
+
+			scheduleFile, err := config.ScheduleFile()
+			if err != nil {
+				return err
+			}
+
+			if dryRun {
+				schedules, err := schedule.Load(scheduleFile)
+				if err != nil {
+					return err
+				}
+
+				now := time.Now()
+				events, err := schedules.Between(now, now.Add(horizon))
+				if err != nil {
+					return err
+				}
+
+				if len(events) == 0 {
+					_, _ = fmt.Fprintf(out, "no scheduled actions in the next %s\n", horizon)
+				}
+				for _, e := range events {
+					_, _ = fmt.Fprintf(out, "%s\n", e)
+				}
+
+				return nil
+			}
+
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(out, "scheduler started, checking every %s\n", interval)
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+
+			last := time.Now()
+			for {
+				select {
+				case <-ctx.Done():
+					// return nil to avoid triggering the error handling in main
+					return nil
+				case now := <-ticker.C:
+					// a missing or broken schedule file shouldn't stop the scheduler, it might be fixed before the next tick
+					schedules, err := schedule.Load(scheduleFile)
+					if err != nil {
+						logger.Error("failed to load schedules", "err", err)
+						continue
+					}
+
+					events, err := schedules.Between(last, now)
+					if err != nil {
+						logger.Error("failed to get scheduled events", "err", err)
+						continue
+					}
+					last = now
+
+					for _, e := range events {
+						applyScheduleEvent(ctx, out, rs, e)
+					}
+				}
+			}
+		},
+	}
+
+	cmd.Flags().Bool(flag.DryRun, false, "print the upcoming actions instead of running the scheduler")
+	cmd.Flags().Duration(flag.Horizon, 24*time.Hour, "how far ahead to look for upcoming actions in a dry-run")
+	cmd.Flags().Duration(flag.Interval, time.Minute, "how often to check for actions to apply")
+
+	return &cmd
+}
+
+func applyScheduleEvent(ctx context.Context, out io.Writer, rs *rockset.RockClient, e schedule.Event) {
+	id, err := lookup.VirtualInstanceNameOrIDtoID(ctx, rs, e.VI)
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "%s: failed: %v\n", e, err)
+		return
+	}
+
+	switch e.Rule.Action {
+	case schedule.Suspend:
+		_, err = rs.SuspendVirtualInstance(ctx, id)
+	case schedule.Resume:
+		_, err = rs.ResumeVirtualInstance(ctx, id)
+	case schedule.Resize:
+		_, err = rs.UpdateVirtualInstance(ctx, id,
+			option.WithVirtualInstanceSize(option.VirtualInstanceSize(e.Rule.Size)))
+	default:
+		err = fmt.Errorf("unknown action %s", e.Rule.Action)
+	}
+
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "%s: failed: %v\n", e, err)
+		return
+	}
+
+	_, _ = fmt.Fprintf(out, "%s: done\n", e)
+}
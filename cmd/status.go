@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rockset/rockset-go-client"
+	"github.com/rockset/rockset-go-client/openapi"
+	"github.com/spf13/cobra"
+
+	"github.com/rockset/cli/completion"
+	"github.com/rockset/cli/config"
+	"github.com/rockset/cli/flag"
+	"github.com/rockset/cli/format"
+	"github.com/rockset/cli/tui"
+)
+
+func newStatusCollectionCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:     "collection [WORKSPACE.]NAME",
+		Aliases: []string{"coll", "c"},
+		Short:   "show collection ingest status",
+		Long: `show the ingest status of a collection, with the state of each source, when it last processed
+a document, how many documents it has processed, and the progress of bulk ingests.
+
+For Kafka sources the lag is the total offset lag of all partitions, which shows if a source is stuck.
+The API doesn't expose per source error counts, so the last status message of the source is shown instead.`,
+		Example: `	## follow the initial load of a collection
+	rockset status collection commons.movies --watch`,
+		Args:              cobra.ExactArgs(1),
+		Annotations:       group("collection"),
+		ValidArgsFunction: completion.Collection(Version),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			ws, name := collectionPath(cmd, args[0])
+			watch, _ := cmd.Flags().GetBool(flag.Watch)
+			interval, _ := cmd.Flags().GetDuration(flag.Interval)
+			if interval <= 0 {
+				return fmt.Errorf("--%s must be positive", flag.Interval)
+			}
+
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			fetch := func(ctx context.Context) (tui.IngestData, error) {
+				return collectionIngestData(ctx, rs, ws, name)
+			}
+
+			if watch {
+				status := tui.NewIngestStatus(ctx, interval, fetch)
+				if _, err = tea.NewProgram(status, tea.WithAltScreen(), tea.WithContext(ctx)).Run(); err != nil {
+					return fmt.Errorf("failed to watch collection: %w", err)
+				}
+				return nil
+			}
+
+			data, err := fetch(ctx)
+			if err != nil {
+				return err
+			}
+
+			if f, _ := cmd.Flags().GetString(flag.Format); f == string(format.JSONFormat) {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(data)
+			}
+
+			_, _ = fmt.Fprint(cmd.OutOrStdout(), tui.RenderIngestStatus(data, 0))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP(flag.Workspace, flag.WorkspaceShort, flag.DefaultWorkspace, "workspace for the collection")
+	_ = cmd.RegisterFlagCompletionFunc(flag.Workspace, completion.Workspace(Version))
+	cmd.Flags().Bool(flag.Watch, false, "refresh the status until stopped")
+	cmd.Flags().Duration(flag.Interval, 5*time.Second, "how often to refresh when watching")
+
+	return &cmd
+}
+
+func collectionIngestData(ctx context.Context, rs *rockset.RockClient, ws, name string) (tui.IngestData, error) {
+	c, err := rs.GetCollection(ctx, ws, name)
+	if err != nil {
+		return tui.IngestData{}, err
+	}
+
+	return CollectionIngestData(c), nil
+}
+
+// CollectionIngestData returns the ingest status of the collection
+func CollectionIngestData(c openapi.Collection) tui.IngestData {
+	stats := c.GetStats()
+	data := tui.IngestData{
+		Collection:   fmt.Sprintf("%s.%s", c.GetWorkspace(), c.GetName()),
+		Status:       c.GetStatus(),
+		Documents:    stats.GetDocCount(),
+		Size:         stats.GetTotalSize(),
+		FillProgress: stats.GetFillProgress(),
+	}
+
+	for _, src := range c.Sources {
+		status := src.GetStatus()
+		s := tui.IngestSource{
+			ID:              src.GetId(),
+			Type:            sourceType(src),
+			Integration:     src.GetIntegrationName(),
+			State:           status.GetState(),
+			Message:         status.GetMessage(),
+			LastProcessedAt: parseISO8601(status.GetLastProcessedAt()),
+			Processed:       status.GetTotalProcessedItems(),
+		}
+
+		if src.Kafka != nil && src.Kafka.Status != nil {
+			// Kafka sources have their own status, which is more detailed
+			ks := src.Kafka.Status
+			if s.State == "" {
+				s.State = ks.GetState()
+			}
+			if t := parseISO8601(ks.GetLastConsumedTime()); !t.IsZero() {
+				s.LastProcessedAt = t
+			}
+			if n := ks.GetNumDocumentsProcessed(); n > s.Processed {
+				s.Processed = n
+			}
+
+			var lag int64
+			for _, p := range ks.KafkaPartitions {
+				lag += p.GetOffsetLag()
+			}
+			s.Lag = &lag
+		}
+
+		data.Sources = append(data.Sources, s)
+	}
+
+	for _, bs := range c.BulkStats {
+		data.Bulk = append(data.Bulk, tui.IngestBulk{
+			Stage:               bulkStage(bs),
+			StartedAt:           parseISO8601(bs.GetStartedAt()),
+			DocumentsDownloaded: bs.GetDocumentsDownloaded(),
+			BytesDownloaded:     bs.GetDataDownloadedBytes(),
+			BytesIndexed:        bs.GetDataIndexedBytes(),
+			Throughput:          bs.GetDataIndexedThroughputBytes(),
+		})
+	}
+
+	return data
+}
+
+// bulkStage returns the first stage of the bulk ingest which isn't done
+func bulkStage(bs openapi.BulkStats) string {
+	stages := []struct {
+		name string
+		done string
+	}{
+		{"PROVISIONING", bs.GetProvisioningStageDoneAt()},
+		{"INITIALIZING", bs.GetInitializingStageDoneAt()},
+		{"DOWNLOADING", bs.GetDownloadingStageDoneAt()},
+		{"INDEXING", bs.GetIndexingStageDoneAt()},
+		{"FINALIZING", bs.GetFinalizingStageDoneAt()},
+	}
+
+	for _, s := range stages {
+		if s.done == "" {
+			return s.name
+		}
+	}
+
+	return "DONE"
+}
+
+func sourceType(src openapi.Source) string {
+	switch {
+	case src.S3 != nil:
+		return "s3"
+	case src.Kafka != nil:
+		return "kafka"
+	case src.Kinesis != nil:
+		return "kinesis"
+	case src.Dynamodb != nil:
+		return "dynamodb"
+	case src.Mongodb != nil:
+		return "mongodb"
+	case src.Gcs != nil:
+		return "gcs"
+	case src.AzureBlobStorage != nil:
+		return "azure"
+	case src.AzureEventHubs != nil:
+		return "eventhubs"
+	case src.AzureServiceBus != nil:
+		return "servicebus"
+	case src.Snowflake != nil:
+		return "snowflake"
+	case src.FileUpload != nil:
+		return "upload"
+	case src.System != nil:
+		return "system"
+	}
+
+	return "unknown"
+}
+
+// parseISO8601 parses an ISO-8601 date from the API, and returns the zero time if it is missing
+func parseISO8601(s string) time.Time {
+	if ms := parseISO8601Millis(s); ms != 0 {
+		return time.UnixMilli(ms)
+	}
+
+	return time.Time{}
+}
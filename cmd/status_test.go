@@ -0,0 +1,77 @@
+package cmd_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rockset/rockset-go-client/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/cli/cmd"
+)
+
+func TestCollectionIngestData(t *testing.T) {
+	c := openapi.Collection{
+		Workspace: openapi.PtrString("commons"),
+		Name:      openapi.PtrString("events"),
+		Status:    openapi.PtrString("READY"),
+		Stats: &openapi.CollectionStats{
+			DocCount:     openapi.PtrInt64(1000),
+			FillProgress: openapi.PtrFloat64(0.5),
+		},
+		Sources: []openapi.Source{
+			{
+				IntegrationName: openapi.PtrString("confluent"),
+				Kafka: &openapi.SourceKafka{
+					Status: &openapi.StatusKafka{
+						State:                 openapi.PtrString("ACTIVE"),
+						LastConsumedTime:      openapi.PtrString("2024-01-02T03:04:05Z"),
+						NumDocumentsProcessed: openapi.PtrInt64(42),
+						KafkaPartitions: []openapi.StatusKafkaPartition{
+							{OffsetLag: openapi.PtrInt64(3)},
+							{OffsetLag: openapi.PtrInt64(4)},
+						},
+					},
+				},
+			},
+			{
+				S3: &openapi.SourceS3{},
+				Status: &openapi.Status{
+					State:   openapi.PtrString("ERROR"),
+					Message: openapi.PtrString("access denied"),
+				},
+			},
+		},
+		BulkStats: []openapi.BulkStats{
+			{
+				ProvisioningStageDoneAt: openapi.PtrString("2024-01-02T03:04:05Z"),
+				InitializingStageDoneAt: openapi.PtrString("2024-01-02T03:04:05Z"),
+				DocumentsDownloaded:     openapi.PtrInt64(10),
+			},
+		},
+	}
+
+	data := cmd.CollectionIngestData(c)
+	assert.Equal(t, "commons.events", data.Collection)
+	assert.Equal(t, int64(1000), data.Documents)
+	assert.Equal(t, 0.5, data.FillProgress)
+
+	require.Len(t, data.Sources, 2)
+	kafka := data.Sources[0]
+	assert.Equal(t, "kafka", kafka.Type)
+	assert.Equal(t, "ACTIVE", kafka.State)
+	assert.Equal(t, int64(42), kafka.Processed)
+	assert.True(t, kafka.LastProcessedAt.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+	require.NotNil(t, kafka.Lag)
+	assert.Equal(t, int64(7), *kafka.Lag)
+
+	s3 := data.Sources[1]
+	assert.Equal(t, "s3", s3.Type)
+	assert.Equal(t, "access denied", s3.Message)
+	assert.Nil(t, s3.Lag)
+	assert.True(t, s3.LastProcessedAt.IsZero())
+
+	require.Len(t, data.Bulk, 1)
+	assert.Equal(t, "DOWNLOADING", data.Bulk[0].Stage)
+}
@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rockset/rockset-go-client"
+	"github.com/rockset/rockset-go-client/option"
+	"github.com/spf13/cobra"
+
+	"github.com/rockset/cli/completion"
+	"github.com/rockset/cli/config"
+	"github.com/rockset/cli/flag"
+	"github.com/rockset/cli/journal"
+	"github.com/rockset/cli/wait"
+)
+
+// swapTarget is replaced by the target collection in the validation query
+const swapTarget = "$TARGET"
+
+// AliasCreated is the state of an alias which can be queried, the go client has no constant for the alias states,
+// and its waiter for an alias only checks that it exists
+const AliasCreated = "CREATED"
+
+func newSwapAliasCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:     "alias NAME",
+		Aliases: []string{"a"},
+		Short:   "swap alias to other collections",
+		Long: `swap an alias to other collections, for blue/green re-ingests without downtime.
+
+Before the alias is updated, the target collections must be READY, and optionally the document counts of
+the current and target collections are compared, and a validation query is run against the target collection,
+which must return at least one row. In the validation query ` + swapTarget + ` is replaced by the target collection.
+
+Each swap is recorded in a local journal, so it can be rolled back to the previous collections using --rollback.
+Rolling back repeatedly goes further back in the history of the alias.`,
+		Example: `	## swap the movies alias to the re-ingested collection, if it has about the same number of documents
+	rockset swap alias movies --to commons.movies_v2 --compare-counts --wait
+
+	## only swap if the new collection has movies from this year
+	rockset swap alias movies --to commons.movies_v2 --validate "SELECT 1 FROM $TARGET WHERE year = 2024 LIMIT 1"
+
+	## roll back to the previous collection
+	rockset swap alias movies --rollback`,
+		Args:              cobra.ExactArgs(1),
+		Annotations:       group("alias"),
+		ValidArgsFunction: completion.Alias(Version),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			name := args[0]
+			ws, _ := cmd.Flags().GetString(flag.Workspace)
+			to, _ := cmd.Flags().GetStringSlice(flag.To)
+			rollback, _ := cmd.Flags().GetBool(flag.Rollback)
+			out := cmd.OutOrStdout()
+
+			if rollback == (len(to) > 0) {
+				return fmt.Errorf("use either --%s or --%s", flag.To, flag.Rollback)
+			}
+
+			contextName, err := config.ContextName(cmd)
+			if err != nil {
+				return err
+			}
+
+			file, err := config.JournalFile()
+			if err != nil {
+				return err
+			}
+
+			j, err := journal.Load(file)
+			if err != nil {
+				return err
+			}
+
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			alias, err := rs.GetAlias(ctx, ws, name)
+			if err != nil {
+				return err
+			}
+			current := alias.Collections
+
+			if rollback {
+				last, found := j.Pop(contextName, ws, name)
+				if !found {
+					return fmt.Errorf("no swap of alias %s.%s recorded in %s", ws, name, file)
+				}
+				if !slices.Equal(last.To, current) {
+					return fmt.Errorf("alias %s.%s points to %s, but the last swap was to %s",
+						ws, name, strings.Join(current, ", "), strings.Join(last.To, ", "))
+				}
+				to = last.From
+			}
+
+			if slices.Equal(to, current) {
+				_, _ = fmt.Fprintf(out, "alias %s.%s already points to %s\n", ws, name, strings.Join(to, ", "))
+				return nil
+			}
+
+			if err = checkSwapTargets(ctx, cmd, rs, current, to); err != nil {
+				return err
+			}
+
+			if err = rs.UpdateAlias(ctx, ws, name, to); err != nil {
+				return err
+			}
+
+			if !rollback {
+				j.Record(journal.Swap{
+					Context:   contextName,
+					Workspace: ws,
+					Alias:     name,
+					From:      current,
+					To:        to,
+					At:        time.Now().UTC(),
+				})
+			}
+			if err = journal.Store(file, j); err != nil {
+				return fmt.Errorf("alias was swapped, but failed to update the journal: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(out, "alias %s.%s swapped from %s to %s\n", ws, name,
+				strings.Join(current, ", "), strings.Join(to, ", "))
+
+			return waitFor(ctx, cmd, wait.Target{
+				Resource: fmt.Sprintf("alias %s.%s", ws, name),
+				Ready:    []string{AliasCreated},
+				State: func(ctx context.Context) (string, error) {
+					a, err := rs.GetAlias(ctx, ws, name)
+					return a.GetState(), err
+				},
+			})
+		},
+	}
+
+	cmd.Flags().StringP(flag.Workspace, flag.WorkspaceShort, flag.DefaultWorkspace, "workspace of the alias")
+	_ = cmd.RegisterFlagCompletionFunc(flag.Workspace, completion.Workspace(Version))
+	cmd.Flags().StringSlice(flag.To, nil, "collections to swap the alias to, as WORKSPACE.NAME")
+	_ = cmd.RegisterFlagCompletionFunc(flag.To, completion.Collection(Version))
+	cmd.Flags().Bool(flag.Rollback, false, "swap the alias back to the collections before the last swap")
+	cmd.MarkFlagsMutuallyExclusive(flag.To, flag.Rollback)
+	cmd.Flags().Bool(flag.CompareCounts, false, "compare the document counts of the current and target collections")
+	cmd.Flags().Float64(flag.MaxCountDiff, 1, "maximum difference in percent of the document counts")
+	cmd.Flags().String(flag.Validate, "", "validation query which must return at least one row")
+	addWaitFlags(&cmd, "the alias is created")
+
+	return &cmd
+}
+
+// checkSwapTargets verifies that the target collections are ready, and runs the optional checks
+func checkSwapTargets(ctx context.Context, cmd *cobra.Command, rs *rockset.RockClient, current, targets []string) error {
+	out := cmd.OutOrStdout()
+
+	var targetCount int64
+	for _, t := range targets {
+		ws, name, found := strings.Cut(t, ".")
+		if !found {
+			return fmt.Errorf("target collection %s must be WORKSPACE.NAME", t)
+		}
+
+		c, err := rs.GetCollection(ctx, ws, name)
+		if err != nil {
+			return fmt.Errorf("failed to get target collection %s: %w", t, err)
+		}
+		if c.GetStatus() != option.CollectionStatusReady.String() {
+			return fmt.Errorf("target collection %s is %s, it must be %s", t, c.GetStatus(),
+				option.CollectionStatusReady)
+		}
+		stats := c.GetStats()
+		targetCount += stats.GetDocCount()
+	}
+
+	if compare, _ := cmd.Flags().GetBool(flag.CompareCounts); compare {
+		var currentCount int64
+		for _, p := range current {
+			ws, name, _ := strings.Cut(p, ".")
+			c, err := rs.GetCollection(ctx, ws, name)
+			if err != nil {
+				return fmt.Errorf("failed to get current collection %s: %w", p, err)
+			}
+			stats := c.GetStats()
+			currentCount += stats.GetDocCount()
+		}
+
+		maxDiff, _ := cmd.Flags().GetFloat64(flag.MaxCountDiff)
+		diff := CountDiff(currentCount, targetCount)
+		_, _ = fmt.Fprintf(out, "current collections have %d documents, target collections have %d (%.2f%%)\n",
+			currentCount, targetCount, diff)
+		if math.Abs(diff) > maxDiff {
+			return fmt.Errorf("the document counts differ by %.2f%%, which is more than %.2f%%", diff, maxDiff)
+		}
+	}
+
+	if sql, _ := cmd.Flags().GetString(flag.Validate); sql != "" {
+		if len(targets) != 1 {
+			return errors.New("a validation query can only be used with a single target collection")
+		}
+
+		sql = strings.ReplaceAll(sql, swapTarget, targets[0])
+		resp, err := rs.Query(ctx, sql)
+		if err != nil {
+			return fmt.Errorf("validation query failed: %w", err)
+		}
+		if resp.GetStatus() == "ERROR" {
+			return fmt.Errorf("validation query failed: %w", queryResponseError(resp))
+		}
+		if len(resp.Results) == 0 {
+			return errors.New("validation query returned no rows")
+		}
+		_, _ = fmt.Fprintf(out, "validation query returned %d rows\n", len(resp.Results))
+	}
+
+	return nil
+}
+
+// CountDiff returns how much the target count differs from the current count, in percent
+func CountDiff(current, target int64) float64 {
+	if current == 0 {
+		if target == 0 {
+			return 0
+		}
+		return 100
+	}
+
+	return 100 * float64(target-current) / float64(current)
+}
@@ -0,0 +1,46 @@
+package cmd_test
+
+import (
+	"go/build"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/rockset/cli/cmd"
+)
+
+func TestCountDiff(t *testing.T) {
+	assert.Equal(t, 0.0, cmd.CountDiff(0, 0))
+	assert.Equal(t, 100.0, cmd.CountDiff(0, 5))
+	assert.Equal(t, 10.0, cmd.CountDiff(100, 110))
+	assert.Equal(t, -50.0, cmd.CountDiff(100, 50))
+}
+
+// TestAliasCreated checks the state the swap waits for against the alias states in the API spec of the go client
+func TestAliasCreated(t *testing.T) {
+	pkg, err := build.Import("github.com/rockset/rockset-go-client/openapi", ".", build.FindOnly)
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(pkg.Dir, "api", "openapi.yaml"))
+	require.NoError(t, err)
+
+	var spec struct {
+		Components struct {
+			Schemas struct {
+				Alias struct {
+					Properties struct {
+						State struct {
+							Enum []string `yaml:"enum"`
+						} `yaml:"state"`
+					} `yaml:"properties"`
+				} `yaml:"Alias"`
+			} `yaml:"schemas"`
+		} `yaml:"components"`
+	}
+	require.NoError(t, yaml.Unmarshal(data, &spec))
+
+	assert.Contains(t, spec.Components.Schemas.Alias.Properties.State.Enum, cmd.AliasCreated)
+}
@@ -15,11 +15,15 @@ import (
 
 func newTestCmd() *cobra.Command {
 	cmd := cobra.Command{
-		Use:    "test",
-		Short:  "test",
-		Hidden: true,
+		Use:   "test",
+		Short: "test resources",
+		Long:  "test Rockset resources",
 	}
 
+	cmd.AddCommand(newTestQueryLambdaCmd())
+	cmd.AddCommand(newTestTransformationCmd())
+
+	// used during development of the tui components
 	cmd.AddCommand(newTestProgressCmd())
 	cmd.AddCommand(newTestInputCmd())
 	cmd.AddCommand(newTestSelectorCmd())
@@ -30,6 +34,7 @@ func newTestCmd() *cobra.Command {
 func newTestInputCmd() *cobra.Command {
 	cmd := cobra.Command{
 		Use:         "input",
+		Hidden:      true,
 		Short:       "test input",
 		Long:        "used for testing input fields",
 		Args:        cobra.NoArgs,
@@ -71,6 +76,7 @@ func newTestInputCmd() *cobra.Command {
 func newTestProgressCmd() *cobra.Command {
 	cmd := cobra.Command{
 		Use:         "progress",
+		Hidden:      true,
 		Short:       "test progress",
 		Long:        "used for testing progress bar",
 		Args:        cobra.NoArgs,
@@ -123,6 +129,7 @@ func newTestProgressCmd() *cobra.Command {
 func newTestSelectorCmd() *cobra.Command {
 	cmd := cobra.Command{
 		Use:         "selector",
+		Hidden:      true,
 		Short:       "test selector",
 		Long:        "used for testing the selector",
 		Args:        cobra.NoArgs,
@@ -0,0 +1 @@
+SELECT kind, COUNT(*) AS count FROM commons._events GROUP BY kind
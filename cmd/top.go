@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rockset/rockset-go-client"
+	"github.com/spf13/cobra"
+
+	"github.com/rockset/cli/completion"
+	"github.com/rockset/cli/config"
+	"github.com/rockset/cli/flag"
+	"github.com/rockset/cli/lookup"
+	"github.com/rockset/cli/tui"
+)
+
+func newTopVirtualInstanceCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:     "virtualinstance ID|NAME",
+		Aliases: []string{"vi"},
+		Short:   "show virtual instance activity",
+		Long: `show the state of a virtual instance, its active queries and mounted collections,
+refreshing periodically. A selected query can be killed.`,
+		Example: `	## refresh every 5 seconds
+	rockset top vi main --interval 5s`,
+		Args:              cobra.ExactArgs(1),
+		Annotations:       group("virtual instance"),
+		ValidArgsFunction: completion.VirtualInstance(Version),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			interval, _ := cmd.Flags().GetDuration(flag.Interval)
+			if interval <= 0 {
+				return fmt.Errorf("--%s must be positive", flag.Interval)
+			}
+
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			id, err := lookup.VirtualInstanceNameOrIDtoID(ctx, rs, args[0])
+			if err != nil {
+				return err
+			}
+
+			top := tui.NewTop(ctx, interval,
+				func(ctx context.Context) (tui.TopData, error) {
+					return virtualInstanceTopData(ctx, rs, id)
+				},
+				func(ctx context.Context, queryID string) error {
+					_, err := rs.CancelQuery(ctx, queryID)
+					return err
+				})
+
+			if _, err = tea.NewProgram(top, tea.WithAltScreen(), tea.WithContext(ctx)).Run(); err != nil {
+				return fmt.Errorf("failed to run top: %w", err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Duration(flag.Interval, 2*time.Second, "how often to refresh")
+
+	return &cmd
+}
+
+func virtualInstanceTopData(ctx context.Context, rs *rockset.RockClient, id string) (tui.TopData, error) {
+	vi, err := rs.GetVirtualInstance(ctx, id)
+	if err != nil {
+		return tui.TopData{}, err
+	}
+
+	queries, err := rs.ListVirtualInstanceQueries(ctx, id)
+	if err != nil {
+		return tui.TopData{}, err
+	}
+
+	mounts, err := rs.ListCollectionMounts(ctx, id)
+	if err != nil {
+		return tui.TopData{}, err
+	}
+
+	data := tui.TopData{
+		Name:  vi.GetName(),
+		State: vi.GetState(),
+		Size:  vi.GetCurrentSize(),
+	}
+
+	now := time.Now()
+	for _, q := range queries {
+		var elapsed time.Duration
+		if submitted := parseISO8601Millis(q.GetSubmittedAt()); submitted > 0 {
+			elapsed = now.Sub(time.UnixMilli(submitted))
+		}
+		data.Queries = append(data.Queries, tui.TopQuery{
+			ID:      q.GetQueryId(),
+			Status:  q.GetStatus(),
+			User:    q.GetExecutedBy(),
+			Elapsed: elapsed,
+			SQL:     q.GetSql(),
+		})
+	}
+
+	for _, m := range mounts {
+		tm := tui.TopMount{
+			Collection: m.GetCollectionPath(),
+			State:      m.GetState(),
+		}
+		if ms := m.GetLastRefreshTimeMillis(); ms > 0 {
+			tm.LastRefresh = time.UnixMilli(ms)
+		}
+		data.Mounts = append(data.Mounts, tm)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rockset/cli/config"
+	"github.com/rockset/cli/flag"
+	"github.com/rockset/cli/format"
+)
+
+func newTestTransformationCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:     "transformation",
+		Aliases: []string{"transform", "t"},
+		Short:   "test an ingest transformation",
+		Long: `run an ingest transformation over sample documents and show the output documents,
+so a transformation can be tested before a collection is created or re-ingested.
+
+The sample file contains either one JSON document per line, or a JSON array of documents.
+The transformation is executed as a query where _input is the sample documents, so functions
+which only are available during ingest can't be tested this way.`,
+		Example: `	## test the transformation in ingest.sql using the documents in docs.ndjson
+	rockset test transformation -I ingest.sql --sample docs.ndjson`,
+		Args:        cobra.NoArgs,
+		Annotations: group("collection"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			sample, _ := cmd.Flags().GetString(flag.Sample)
+
+			transformation, err := ingestTransformation(cmd)
+			if err != nil {
+				return err
+			}
+			if transformation == "" {
+				return fmt.Errorf("specify the transformation using --%s or --%s",
+					flag.IngestTransformation, flag.IngestTransformationFile)
+			}
+
+			data, err := os.ReadFile(sample)
+			if err != nil {
+				return err
+			}
+
+			docs, err := ParseSampleDocuments(data)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s: %w", sample, err)
+			}
+
+			sql, err := TransformationSQL(transformation, docs)
+			if err != nil {
+				return err
+			}
+			logger.Debug("testing transformation", "sql", sql)
+
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			resp, err := rs.Query(ctx, sql)
+			if err != nil {
+				return err
+			}
+			if resp.GetStatus() == "ERROR" {
+				return queryResponseError(resp)
+			}
+
+			out := cmd.OutOrStdout()
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "%d input documents, %d output documents\n",
+				len(docs), len(resp.Results))
+
+			if f, _ := cmd.Flags().GetString(flag.Format); f == string(format.JSONFormat) {
+				enc := json.NewEncoder(out)
+				for _, doc := range resp.Results {
+					if err = enc.Encode(doc); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+
+			return showQueryResponse(out, resp)
+		},
+	}
+
+	cmd.Flags().String(flag.IngestTransformation, "", "ingest transformation SQL")
+	cmd.Flags().StringP(flag.IngestTransformationFile, "I", "", "read ingest transformation SQL from file")
+	_ = cobra.MarkFlagFilename(cmd.Flags(), flag.IngestTransformationFile, ".sql")
+	cmd.MarkFlagsMutuallyExclusive(flag.IngestTransformation, flag.IngestTransformationFile)
+
+	cmd.Flags().String(flag.Sample, "", "file with sample input documents")
+	_ = cobra.MarkFlagFilename(cmd.Flags(), flag.Sample, ".json", ".ndjson", ".jsonl")
+	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.Sample)
+
+	return &cmd
+}
+
+// ParseSampleDocuments parses either a JSON array of documents, or one JSON document per line
+func ParseSampleDocuments(data []byte) ([]map[string]any, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errors.New("no documents")
+	}
+
+	var docs []map[string]any
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &docs); err != nil {
+			return nil, err
+		}
+		return docs, nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	// allow documents up to 10 MiB
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	var line int
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		var doc map[string]any
+		if err := json.Unmarshal(text, &doc); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		docs = append(docs, doc)
+	}
+
+	return docs, scanner.Err()
+}
+
+var withClause = regexp.MustCompile(`(?is)^\s*WITH\s+`)
+
+// TransformationSQL returns a query which applies the ingest transformation to the documents,
+// by defining _input as a common table expression with the documents
+func TransformationSQL(transformation string, docs []map[string]any) (string, error) {
+	data, err := json.Marshal(docs)
+	if err != nil {
+		return "", err
+	}
+
+	input := fmt.Sprintf("_input AS (SELECT * FROM UNNEST(JSON_PARSE('%s')))",
+		strings.ReplaceAll(string(data), "'", "''"))
+
+	transformation = strings.TrimSuffix(strings.TrimSpace(transformation), ";")
+	if loc := withClause.FindStringIndex(transformation); loc != nil {
+		// the transformation has its own common table expressions, so add _input as the first one
+		return fmt.Sprintf("WITH %s,\n%s", input, transformation[loc[1]:]), nil
+	}
+
+	return fmt.Sprintf("WITH %s\n%s", input, transformation), nil
+}
@@ -0,0 +1,43 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/cli/cmd"
+)
+
+func TestParseSampleDocuments(t *testing.T) {
+	docs, err := cmd.ParseSampleDocuments([]byte(`{"a": 1}
+
+{"a": 2, "b": "x"}
+`))
+	require.NoError(t, err)
+	assert.Len(t, docs, 2)
+
+	docs, err = cmd.ParseSampleDocuments([]byte(`[{"a": 1}, {"a": 2}, {"a": 3}]`))
+	require.NoError(t, err)
+	assert.Len(t, docs, 3)
+
+	_, err = cmd.ParseSampleDocuments([]byte("{\"a\": 1}\nnot json\n"))
+	assert.ErrorContains(t, err, "line 2")
+
+	_, err = cmd.ParseSampleDocuments([]byte("  "))
+	assert.Error(t, err)
+}
+
+func TestTransformationSQL(t *testing.T) {
+	docs := []map[string]any{{"name": "it's"}}
+
+	sql, err := cmd.TransformationSQL("SELECT name FROM _input;", docs)
+	require.NoError(t, err)
+	assert.Equal(t, `WITH _input AS (SELECT * FROM UNNEST(JSON_PARSE('[{"name":"it''s"}]')))
+SELECT name FROM _input`, sql)
+
+	sql, err = cmd.TransformationSQL("with x AS (SELECT * FROM _input) SELECT * FROM x", docs)
+	require.NoError(t, err)
+	assert.Equal(t, `WITH _input AS (SELECT * FROM UNNEST(JSON_PARSE('[{"name":"it''s"}]'))),
+x AS (SELECT * FROM _input) SELECT * FROM x`, sql)
+}
@@ -1,13 +1,19 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/rockset/rockset-go-client/openapi"
 	"github.com/spf13/cobra"
 
+	"github.com/rockset/cli/completion"
 	"github.com/rockset/cli/config"
 	"github.com/rockset/cli/flag"
 	"github.com/rockset/cli/format"
 	"github.com/rockset/cli/sort"
+	"github.com/rockset/cli/users"
 )
 
 func newListUsersCmd() *cobra.Command {
@@ -79,3 +85,224 @@ func newGetUserCmd() *cobra.Command {
 
 	return &cmd
 }
+
+func newCreateUserCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:     "user EMAIL",
+		Aliases: []string{"u"},
+		Args:    cobra.ExactArgs(1),
+		Short:   "invite user",
+		Long:    "invite a user to the Rockset organization, with the roles it should have",
+		Example: `	## invite a user as read only
+	rockset create user alice@example.com --role read-only`,
+		Annotations: group("user"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			roles, _ := cmd.Flags().GetStringSlice(flag.Role)
+
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			user, err := rs.CreateUser(ctx, args[0], roles)
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "user %s invited with roles %s\n", user.GetEmail(),
+				strings.Join(user.Roles, ", "))
+
+			return nil
+		},
+	}
+
+	addUserRoleFlag(&cmd, "roles of the user")
+
+	return &cmd
+}
+
+func newUpdateUserCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:               "user EMAIL",
+		Aliases:           []string{"u"},
+		Args:              cobra.ExactArgs(1),
+		Short:             "update user roles",
+		Long:              "update the roles of a user, which replaces the current roles",
+		Annotations:       group("user"),
+		ValidArgsFunction: completion.Email(Version),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			roles, _ := cmd.Flags().GetStringSlice(flag.Role)
+
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			user, err := rs.UpdateUser(ctx, args[0], roles)
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "user %s updated with roles %s\n", user.GetEmail(),
+				strings.Join(user.Roles, ", "))
+
+			return nil
+		},
+	}
+
+	addUserRoleFlag(&cmd, "roles of the user, replacing the current roles")
+
+	return &cmd
+}
+
+func newDeleteUserCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:               "user EMAIL",
+		Aliases:           []string{"u"},
+		Args:              cobra.ExactArgs(1),
+		Short:             "remove user",
+		Long:              "remove a user from the Rockset organization",
+		Annotations:       group("user"),
+		ValidArgsFunction: completion.Email(Version),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			if ok, err := confirmDelete(cmd, []string{"user " + args[0]}); err != nil || !ok {
+				return err
+			}
+
+			if err = rs.DeleteUser(ctx, args[0]); err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "user %s removed\n", args[0])
+
+			return nil
+		},
+	}
+
+	addDeleteFlags(&cmd)
+
+	return &cmd
+}
+
+func newSyncUsersCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:     "users",
+		Aliases: []string{"user", "u"},
+		Args:    cobra.NoArgs,
+		Short:   "sync the users of the organization",
+		Long: `invite the users listed in the file which aren't members of the organization, update the roles of the
+members whose roles differ, and remove the members which aren't listed.
+
+The current user is never removed, so it must be listed in the file. Removing users asks for confirmation,
+unless --yes is used.`,
+		Example: `	## users.yaml
+	users:
+	  - email: alice@example.com
+	    roles: [admin]
+	  - email: bob@example.com
+	    roles: [member, read-only]
+
+	## show what would change
+	rockset sync users -f users.yaml --dry-run`,
+		Annotations: group("user"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			file, _ := cmd.Flags().GetString(flag.File)
+			dryRun, _ := cmd.Flags().GetBool(flag.DryRun)
+			out := cmd.OutOrStdout()
+
+			desired, err := users.Load(file)
+			if err != nil {
+				return err
+			}
+
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			self, err := rs.GetCurrentUser(ctx)
+			if err != nil {
+				return err
+			}
+
+			current, err := rs.ListUsers(ctx)
+			if err != nil {
+				return err
+			}
+
+			changes, err := users.Plan(current, desired, self.GetEmail())
+			if err != nil {
+				return err
+			}
+
+			if len(changes) == 0 {
+				_, _ = fmt.Fprintln(out, "users are in sync")
+				return nil
+			}
+
+			var removed []string
+			for _, c := range changes {
+				_, _ = fmt.Fprintln(out, c)
+				if c.Action == users.Remove {
+					removed = append(removed, "user "+c.Email)
+				}
+			}
+			if dryRun {
+				return nil
+			}
+
+			if len(removed) > 0 {
+				if ok, err := confirmDelete(cmd, removed); err != nil || !ok {
+					return err
+				}
+			}
+
+			var errs []error
+			for _, c := range changes {
+				switch c.Action {
+				case users.Invite:
+					_, err = rs.CreateUser(ctx, c.Email, c.To)
+				case users.Update:
+					_, err = rs.UpdateUser(ctx, c.Email, c.To)
+				case users.Remove:
+					err = rs.DeleteUser(ctx, c.Email)
+				}
+				if err != nil {
+					errs = append(errs, fmt.Errorf("failed to %s %s: %w", c.Action, c.Email, err))
+				}
+			}
+
+			if len(errs) > 0 {
+				return errors.Join(errs...)
+			}
+
+			_, _ = fmt.Fprintf(out, "synced %s\n", plural(len(changes), "user"))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP(flag.File, "f", "", "YAML file with the users and their roles")
+	_ = cmd.MarkFlagRequired(flag.File)
+	_ = cobra.MarkFlagFilename(cmd.Flags(), flag.File, ".yaml", ".yml")
+	cmd.Flags().Bool(flag.DryRun, false, "only show what would change")
+	cmd.Flags().BoolP(flag.Yes, "y", false, "remove users without asking for confirmation")
+
+	return &cmd
+}
+
+func addUserRoleFlag(cmd *cobra.Command, usage string) {
+	cmd.Flags().StringSlice(flag.Role, nil, usage)
+	_ = cmd.MarkFlagRequired(flag.Role)
+	_ = cmd.RegisterFlagCompletionFunc(flag.Role, completion.Role(Version))
+}
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 )
 
@@ -11,6 +13,24 @@ func addVerbs(root *cobra.Command) {
 		Long:  "authenticate using an bearer token or an apikey",
 	}
 
+	benchCmd := cobra.Command{
+		Use:   "bench",
+		Short: "benchmark queries",
+		Long:  "benchmark Rockset queries and query lambdas",
+	}
+
+	compareCmd := cobra.Command{
+		Use:   "compare",
+		Short: "compare query results",
+		Long:  "compare Rockset query results",
+	}
+
+	copyCmd := cobra.Command{
+		Use:   "copy",
+		Short: "copy resources",
+		Long:  "copy Rockset resources",
+	}
+
 	createCmd := cobra.Command{
 		Use:     "create",
 		Aliases: []string{"c"},
@@ -24,6 +44,18 @@ func addVerbs(root *cobra.Command) {
 		Long:  "delete Rockset resource",
 	}
 
+	describeCmd := cobra.Command{
+		Use:   "describe",
+		Short: "describe resources",
+		Long:  "describe the structure of Rockset resources",
+	}
+
+	diffCmd := cobra.Command{
+		Use:   "diff",
+		Short: "diff resources",
+		Long:  "show differences between Rockset resources",
+	}
+
 	executeCmd := cobra.Command{
 		Use:     "execute",
 		Aliases: []string{"exec", "e"},
@@ -38,6 +70,12 @@ func addVerbs(root *cobra.Command) {
 		Long:    "get Rockset resources",
 	}
 
+	grantCmd := cobra.Command{
+		Use:   "grant",
+		Short: "grant privileges",
+		Long:  "grant privileges to Rockset roles",
+	}
+
 	listCmd := cobra.Command{
 		Use:     "list",
 		Aliases: []string{"l"},
@@ -57,18 +95,66 @@ func addVerbs(root *cobra.Command) {
 		Long:  "resume Rockset resources",
 	}
 
+	revokeCmd := cobra.Command{
+		Use:   "revoke",
+		Short: "revoke privileges",
+		Long:  "revoke privileges from Rockset roles",
+	}
+
+	scheduleCmd := cobra.Command{
+		Use:   "schedule",
+		Short: "schedule resources",
+		Long:  "schedule actions on Rockset resources",
+	}
+
+	schedulerCmd := cobra.Command{
+		Use:   "scheduler",
+		Short: "manage the scheduler",
+		Long:  "manage the scheduler which applies the schedules",
+	}
+
+	statsCmd := cobra.Command{
+		Use:   "stats",
+		Short: "show resource statistics",
+		Long:  "show usage statistics for Rockset resources",
+	}
+
 	suspendCmd := cobra.Command{
 		Use:   "suspend",
 		Short: "suspend resources",
 		Long:  "suspend Rockset resources",
 	}
 
+	statusCmd := cobra.Command{
+		Use:   "status",
+		Short: "show resource status",
+		Long:  "show the status of Rockset resources",
+	}
+
+	swapCmd := cobra.Command{
+		Use:   "swap",
+		Short: "swap resources",
+		Long:  "swap Rockset resources to point to other resources",
+	}
+
+	syncCmd := cobra.Command{
+		Use:   "sync",
+		Short: "sync resources",
+		Long:  "sync Rockset resources with a local file",
+	}
+
 	tailCmd := cobra.Command{
 		Use:   "tail",
 		Short: "tail collections",
 		Long:  "tail Rockset collections",
 	}
 
+	topCmd := cobra.Command{
+		Use:   "top",
+		Short: "show resource activity",
+		Long:  "show live activity of Rockset resources",
+	}
+
 	updateCmd := cobra.Command{
 		Use:   "update",
 		Short: "update resources",
@@ -90,26 +176,50 @@ func addVerbs(root *cobra.Command) {
 
 	createCmd.AddCommand(&sampleCmd)
 
-	// s3
-	s3Cmd := cobra.Command{
-		Use:   "s3",
-		Short: "create s3 resources",
-		Long:  "s3 integration and collection commands",
-	}
+	// sources
+	s3Cmd := sourceCmd("s3", "S3")
+	kafkaCmd := sourceCmd("kafka", "Kafka")
+	kafkaCmd.Aliases = []string{"confluent"}
+	kinesisCmd := sourceCmd("kinesis", "Kinesis")
+	dynamoDBCmd := sourceCmd("dynamodb", "DynamoDB")
+	mongoDBCmd := sourceCmd("mongodb", "MongoDB")
+	gcsCmd := sourceCmd("gcs", "GCS")
+	azureCmd := sourceCmd("azure", "Azure Blob Storage")
+	snowflakeCmd := sourceCmd("snowflake", "Snowflake")
+	mskCmd := sourceCmd("msk", "MSK")
 
 	// authentication
 	authCmd.AddCommand(newAuthLoginCmd())
 	authCmd.AddCommand(newAuthKeyCmd())
 	authCmd.AddCommand(newAuthRefreshCmd())
+	authCmd.AddCommand(newAuthCanICmd())
 
-	createCmd.AddCommand(&s3Cmd)
+	createCmd.AddCommand(s3Cmd, kafkaCmd, kinesisCmd, dynamoDBCmd, mongoDBCmd, gcsCmd, azureCmd, snowflakeCmd,
+		mskCmd)
 	s3Cmd.AddCommand(newCreateS3CollectionCmd())
 	s3Cmd.AddCommand(newCreateS3IntegrationsCmd())
+	kafkaCmd.AddCommand(newCreateKafkaCollectionCmd())
+	kinesisCmd.AddCommand(newCreateKinesisCollectionCmd())
+	dynamoDBCmd.AddCommand(newCreateDynamoDBCollectionCmd())
+	mongoDBCmd.AddCommand(newCreateMongoDBCollectionCmd())
+	gcsCmd.AddCommand(newCreateGCSCollectionCmd())
+	azureCmd.AddCommand(newCreateAzureCollectionCmd())
+	snowflakeCmd.AddCommand(newCreateSnowflakeCollectionCmd())
+	kafkaCmd.AddCommand(newCreateKafkaIntegrationCmd())
+	mskCmd.AddCommand(newCreateMSKIntegrationCmd())
+	kinesisCmd.AddCommand(newCreateKinesisIntegrationCmd())
+	dynamoDBCmd.AddCommand(newCreateDynamoDBIntegrationCmd())
+	mongoDBCmd.AddCommand(newCreateMongoDBIntegrationCmd())
+	gcsCmd.AddCommand(newCreateGCSIntegrationCmd())
+	azureCmd.AddCommand(newCreateAzureIntegrationCmd())
+	snowflakeCmd.AddCommand(newCreateSnowflakeIntegrationCmd())
 
 	// collections
 	createCmd.AddCommand(newCreateCollectionCmd())
 	deleteCmd.AddCommand(newDeleteCollectionCmd())
+	updateCmd.AddCommand(newUpdateCollectionCmd())
 	getCmd.AddCommand(newGetCollectionCmd())
+	describeCmd.AddCommand(newDescribeCollectionCmd())
 	listCmd.AddCommand(newListCollectionsCmd())
 	sampleCmd.AddCommand(newCreateSampleCollectionCmd())
 	tailCmd.AddCommand(newCreateTailCollectionCmd())
@@ -118,6 +228,7 @@ func addVerbs(root *cobra.Command) {
 	deleteCmd.AddCommand(newDeleteIntegrationsCmd())
 	getCmd.AddCommand(newGetIntegrationCmd())
 	listCmd.AddCommand(newListIntegrationsCmd())
+	updateCmd.AddCommand(newUpdateIntegrationCmd())
 
 	// query
 	getCmd.AddCommand(&queryCmd)
@@ -125,17 +236,26 @@ func addVerbs(root *cobra.Command) {
 	queryCmd.AddCommand(newGetQueryInfoCmd())   // get query info
 	queryCmd.AddCommand(newGetQueryResultCmd()) // get query result
 	root.AddCommand(newQueryCmd())              // execute a query
+	benchCmd.AddCommand(newBenchQueryCmd())     // benchmark a query
+	compareCmd.AddCommand(newCompareQueryCmd()) // compare query results
 
 	// org
 	getCmd.AddCommand(newGetOrganizationCmd())
 
 	// users
+	createCmd.AddCommand(newCreateUserCmd())
+	deleteCmd.AddCommand(newDeleteUserCmd())
 	getCmd.AddCommand(newGetUserCmd())
 	listCmd.AddCommand(newListUsersCmd())
+	updateCmd.AddCommand(newUpdateUserCmd())
+	syncCmd.AddCommand(newSyncUsersCmd())
 
 	// views
 	getCmd.AddCommand(newGetViewCmd())
 	listCmd.AddCommand(newListViewsCmd())
+	createCmd.AddCommand(newCreateViewCmd())
+	updateCmd.AddCommand(newUpdateViewCmd())
+	deleteCmd.AddCommand(newDeleteViewCmd())
 
 	// virtual instances
 	createCmd.AddCommand(newCreateVirtualInstanceCmd())
@@ -145,6 +265,10 @@ func addVerbs(root *cobra.Command) {
 	resumeCmd.AddCommand(newResumeVirtualInstanceCmd())
 	suspendCmd.AddCommand(newSuspendVirtualInstanceCmd())
 	updateCmd.AddCommand(newUpdateVirtualInstanceCmd())
+	scheduleCmd.AddCommand(newScheduleVirtualInstanceCmd())
+	topCmd.AddCommand(newTopVirtualInstanceCmd())
+	statusCmd.AddCommand(newStatusCollectionCmd())
+	schedulerCmd.AddCommand(newSchedulerRunCmd())
 
 	// aliases
 	getCmd.AddCommand(NewGetAliasCmd())
@@ -152,16 +276,23 @@ func addVerbs(root *cobra.Command) {
 	createCmd.AddCommand(NewCreateAliasCmd())
 	deleteCmd.AddCommand(NewDeleteAliasCmd())
 	updateCmd.AddCommand(NewUpdateAliasCmd())
+	swapCmd.AddCommand(newSwapAliasCmd())
 
 	// mounts
 	listCmd.AddCommand(NewListMountsCmd())
 	getCmd.AddCommand(NewGetMountCmd())
 	root.AddCommand(NewMountCollectionsCmd())
 	root.AddCommand(NewUnmountCollectionCmd())
+	syncCmd.AddCommand(newSyncMountsCmd())
 
 	// roles
+	createCmd.AddCommand(newCreateRoleCommand())
+	deleteCmd.AddCommand(newDeleteRoleCommand())
 	getCmd.AddCommand(newGetRoleCommand())
 	listCmd.AddCommand(newListRolesCommand())
+	updateCmd.AddCommand(newUpdateRoleCommand())
+	grantCmd.AddCommand(newGrantPrivilegeCommand())
+	revokeCmd.AddCommand(newRevokePrivilegeCommand())
 
 	// API keys
 	createCmd.AddCommand(NewCreateAPIKeyCmd())
@@ -174,9 +305,11 @@ func addVerbs(root *cobra.Command) {
 	createCmd.AddCommand(newCreateQueryLambdaCmd())
 	deleteCmd.AddCommand(newDeleteQueryLambdaCmd())
 	updateCmd.AddCommand(newUpdateQueryLambdaCmd())
+	diffCmd.AddCommand(newDiffQueryLambdaCmd())
 	executeCmd.AddCommand(NewExecuteQueryLambdaCmd())
 	getCmd.AddCommand(newGetQueryLambdaCmd())
 	listCmd.AddCommand(newListQueryLambdasCmd())
+	statsCmd.AddCommand(newStatsQueryLambdasCmd())
 
 	// documents
 	deleteCmd.AddCommand(newDeleteDocumentsCmd())
@@ -184,6 +317,7 @@ func addVerbs(root *cobra.Command) {
 	// workspace
 	createCmd.AddCommand(newCreateWorkspaceCmd())
 	deleteCmd.AddCommand(newDeleteWorkspaceCmd())
+	copyCmd.AddCommand(newCopyWorkspaceCmd())
 	getCmd.AddCommand(NewGetWorkspaceCmd())
 	listCmd.AddCommand(newListWorkspacesCmd())
 
@@ -194,17 +328,33 @@ func addVerbs(root *cobra.Command) {
 	useCmd.AddCommand(newUseContextCmd())
 
 	root.AddCommand(&authCmd)
+	root.AddCommand(&benchCmd)
+	root.AddCommand(&compareCmd)
+	root.AddCommand(&copyCmd)
 	root.AddCommand(&createCmd)
 	root.AddCommand(&deleteCmd)
+	root.AddCommand(&describeCmd)
+	root.AddCommand(&diffCmd)
 	root.AddCommand(&executeCmd)
 	root.AddCommand(&getCmd)
+	root.AddCommand(&grantCmd)
 	root.AddCommand(&listCmd)
 	root.AddCommand(&resumeCmd)
+	root.AddCommand(&revokeCmd)
+	root.AddCommand(&scheduleCmd)
+	root.AddCommand(&schedulerCmd)
+	root.AddCommand(&statsCmd)
+	root.AddCommand(&statusCmd)
 	root.AddCommand(&suspendCmd)
+	root.AddCommand(&swapCmd)
+	root.AddCommand(&syncCmd)
 	root.AddCommand(&tailCmd)
+	root.AddCommand(&topCmd)
 	root.AddCommand(&updateCmd)
 	root.AddCommand(&useCmd)
+	root.AddCommand(newGraphCmd())
 	root.AddCommand(newVersionCmd())
+	root.AddCommand(newWhoAmICmd())
 
 	root.AddCommand(newIngestCmd())
 
@@ -212,3 +362,12 @@ func addVerbs(root *cobra.Command) {
 
 	// TODO set help func for the root command to show commands grouped by the resource they operate on
 }
+
+// sourceCmd returns a command which groups the integration and collection commands of a data source
+func sourceCmd(use, name string) *cobra.Command {
+	return &cobra.Command{
+		Use:   use,
+		Short: fmt.Sprintf("create %s resources", name),
+		Long:  fmt.Sprintf("%s integration and collection commands", name),
+	}
+}
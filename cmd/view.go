@@ -1,6 +1,12 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rockset/rockset-go-client"
 	"github.com/rockset/rockset-go-client/openapi"
 	"github.com/rockset/rockset-go-client/option"
 	"github.com/spf13/cobra"
@@ -9,9 +15,15 @@ import (
 	"github.com/rockset/cli/config"
 	"github.com/rockset/cli/flag"
 	"github.com/rockset/cli/format"
+	"github.com/rockset/cli/graph"
 	"github.com/rockset/cli/sort"
+	"github.com/rockset/cli/wait"
 )
 
+// viewCreated is the state of a view which is ready to be queried. The go client has neither a constant
+// for the view states nor a waiter for a view being created, only for it being gone.
+const viewCreated = "CREATED"
+
 func newListViewsCmd() *cobra.Command {
 	cmd := cobra.Command{
 		Use:         "views",
@@ -84,3 +96,185 @@ func newGetViewCmd() *cobra.Command {
 
 	return &cmd
 }
+
+func newCreateViewCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:     "view NAME",
+		Aliases: []string{"v"},
+		Args:    cobra.ExactArgs(1),
+		Short:   "create view",
+		Long: `create a view from the SQL in a file, and show the collections and views it depends on
+
+	# Documentation URL
+	https://docs.rockset.com/documentation/reference/createview`,
+		Example: `	## create a view and wait until it can be queried
+	rockset create view top_movies --sql top_movies.sql --wait`,
+		Annotations: group("view"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ws, _ := cmd.Flags().GetString(flag.Workspace)
+
+			sql, options, err := viewFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			view, err := rs.CreateView(ctx, ws, args[0], sql, options...)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "created view %s.%s\n", ws, args[0])
+
+			return waitForView(ctx, cmd, rs, view)
+		},
+	}
+	addViewFlags(&cmd)
+
+	return &cmd
+}
+
+func newUpdateViewCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:     "view NAME",
+		Aliases: []string{"v"},
+		Args:    cobra.ExactArgs(1),
+		Short:   "update view",
+		Long: `update the SQL of a view, and show the collections and views it depends on
+
+	# Documentation URL
+	https://docs.rockset.com/documentation/reference/updateview`,
+		Annotations:       group("view"),
+		ValidArgsFunction: completion.View(Version),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ws, _ := cmd.Flags().GetString(flag.Workspace)
+
+			sql, options, err := viewFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			view, err := rs.UpdateView(ctx, ws, args[0], sql, options...)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "updated view %s.%s\n", ws, args[0])
+
+			return waitForView(ctx, cmd, rs, view)
+		},
+	}
+	addViewFlags(&cmd)
+
+	return &cmd
+}
+
+func newDeleteViewCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:     "view NAME",
+		Aliases: []string{"v"},
+		Args:    cobra.ExactArgs(1),
+		Short:   "delete view",
+		Long: `delete a view
+
+	# Documentation URL
+	https://docs.rockset.com/documentation/reference/deleteview`,
+		Annotations:       group("view"),
+		ValidArgsFunction: completion.View(Version),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ws, _ := cmd.Flags().GetString(flag.Workspace)
+			name := args[0]
+
+			ctx := cmd.Context()
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
+			}
+
+			warnBrokenDependents(ctx, cmd, rs, graph.Node{Kind: graph.View, Workspace: ws, Name: name})
+
+			if ok, err := confirmDelete(cmd, []string{fmt.Sprintf("view %s.%s", ws, name)}); err != nil || !ok {
+				return err
+			}
+
+			if err = rs.DeleteView(ctx, ws, name); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "deleted view %s.%s\n", ws, name)
+
+			return waitUsing(ctx, cmd, fmt.Sprintf("view %s.%s is deleted", ws, name),
+				func(ctx context.Context) error {
+					return rs.Wait.UntilViewGone(ctx, ws, name)
+				})
+		},
+	}
+	cmd.Flags().StringP(flag.Workspace, flag.WorkspaceShort, flag.DefaultWorkspace, "workspace of the view")
+	_ = cmd.RegisterFlagCompletionFunc(flag.Workspace, completion.Workspace(Version))
+	addDeleteFlags(&cmd)
+	addWaitFlags(&cmd, "view is deleted")
+
+	return &cmd
+}
+
+func addViewFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP(flag.Workspace, flag.WorkspaceShort, flag.DefaultWorkspace, "workspace of the view")
+	_ = cmd.RegisterFlagCompletionFunc(flag.Workspace, completion.Workspace(Version))
+
+	cmd.Flags().String(flag.Description, "", "description of the view")
+	addWaitFlags(cmd, "view is created")
+
+	cmd.Flags().String(flag.SQL, "", "file containing SQL")
+	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.SQL)
+	_ = cobra.MarkFlagFilename(cmd.Flags(), flag.SQL, ".sql")
+}
+
+func viewFlags(cmd *cobra.Command) (string, []option.ViewOption, error) {
+	sqlFile, _ := cmd.Flags().GetString(flag.SQL)
+	sql, err := os.ReadFile(sqlFile)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var options []option.ViewOption
+	if description, _ := cmd.Flags().GetString(flag.Description); description != "" {
+		options = append(options, option.WithViewDescription(description))
+	}
+
+	return string(sql), options, nil
+}
+
+// waitForView waits until the view is created if --wait is used, and then shows what it depends on,
+// which is only known once the view is created
+func waitForView(ctx context.Context, cmd *cobra.Command, rs *rockset.RockClient, view openapi.View) error {
+	ws, name := view.GetWorkspace(), view.GetName()
+
+	err := waitFor(ctx, cmd, wait.Target{
+		Resource: fmt.Sprintf("view %s.%s", ws, name),
+		Ready:    []string{viewCreated},
+		State: func(ctx context.Context) (string, error) {
+			v, err := rs.GetView(ctx, ws, name)
+			if err == nil {
+				view = v
+			}
+			return v.GetState(), err
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(view.Entities) > 0 {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "depends on: %s\n", strings.Join(view.Entities, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+//go:build integration
+
+package cmd_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rockset/cli/cmd"
+	"github.com/rockset/cli/internal/test"
+)
+
+type ViewTestSuite struct {
+	suite.Suite
+	name string
+}
+
+func TestViewSuite(t *testing.T) {
+	test.SkipUnlessIntegrationTest(t)
+
+	s := ViewTestSuite{name: "cli_view"} // TODO use random name
+	suite.Run(t, &s)
+}
+
+func (s *ViewTestSuite) Test_0_Create() {
+	c := cmd.NewRootCmd("test")
+	out := test.WrapAndExecute(s.T(), c, "create", "view", s.name, "--sql", "testdata/view.sql", "--wait")
+
+	s.Contains(out.String(), fmt.Sprintf("created view commons.%s\n", s.name))
+}
+
+func (s *ViewTestSuite) Test_1_Update() {
+	c := cmd.NewRootCmd("test")
+	out := test.WrapAndExecute(s.T(), c, "update", "view", s.name, "--sql", "testdata/view.sql",
+		"--description", "updated")
+
+	s.Contains(out.String(), fmt.Sprintf("updated view commons.%s\n", s.name))
+}
+
+func (s *ViewTestSuite) Test_2_Delete() {
+	c := cmd.NewRootCmd("test")
+	out := test.WrapAndExecute(s.T(), c, "delete", "view", s.name, "--wait", "--yes")
+
+	s.Contains(out.String(), fmt.Sprintf("deleted view commons.%s\n", s.name))
+}
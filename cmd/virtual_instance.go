@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"github.com/rockset/cli/lookup"
+	"strings"
+	"time"
+
 	"github.com/rockset/rockset-go-client"
+	rockerr "github.com/rockset/rockset-go-client/errors"
 	"github.com/rockset/rockset-go-client/openapi"
 	"github.com/rockset/rockset-go-client/option"
 	"github.com/spf13/cobra"
@@ -12,9 +16,30 @@ import (
 	"github.com/rockset/cli/config"
 	"github.com/rockset/cli/flag"
 	"github.com/rockset/cli/format"
+	"github.com/rockset/cli/lookup"
 	"github.com/rockset/cli/sort"
+	"github.com/rockset/cli/wait"
 )
 
+// virtualInstanceSizes are the sizes which can be used when creating or updating a virtual instance
+var virtualInstanceSizes = []option.VirtualInstanceSize{
+	option.SizeFree,
+	option.SizeNano,
+	option.SizeShared,
+	option.SizeMilli,
+	option.SizeSmall,
+	option.SizeMedium,
+	option.SizeLarge,
+	option.SizeXLarge,
+	option.SizeXLarge2,
+	option.SizeXLarge4,
+	option.SizeXLarge8,
+	option.SizeXLarge16,
+}
+
+// minAutoSuspend is the shortest auto-suspend duration the API accepts
+const minAutoSuspend = 15 * time.Minute
+
 func newCreateVirtualInstanceCmd() *cobra.Command {
 	cmd := cobra.Command{
 		Use:         "virtualinstance ID|NAME",
@@ -25,28 +50,35 @@ func newCreateVirtualInstanceCmd() *cobra.Command {
 		Annotations: group("virtual instance"),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
-			size, _ := cmd.Flags().GetString(flag.Size)
 
-			rs, err := config.Client(cmd, Version)
+			options, err := virtualInstanceOptions(cmd)
 			if err != nil {
 				return err
 			}
 
-			var options = []option.VirtualInstanceOption{
-				option.WithVirtualInstanceSize(option.VirtualInstanceSize(size)),
+			rs, err := config.Client(cmd, Version)
+			if err != nil {
+				return err
 			}
 
-			// TODO uncomment after go client supports setting it
-			//if desc, _ := cmd.Flags().GetString(flag.Description); desc != "" {
-			//options = append(options, option.WithVirtualInstanceDescription(desc))
-			//}
-
 			result, err := rs.CreateVirtualInstance(ctx, args[0], options...)
 			if err != nil {
 				return err
 			}
+
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "virtual instance '%s' created\n", result.GetName())
 
+			// the mount refresh interval is set separately, once the virtual instance exists
+			if cmd.Flags().Changed(flag.MountRefreshInterval) {
+				interval, _ := cmd.Flags().GetDuration(flag.MountRefreshInterval)
+				if err = setMountRefreshInterval(ctx, rs, result.GetId(), interval); err != nil {
+					return fmt.Errorf("virtual instance '%s' was created, but setting the mount refresh interval failed: %w",
+						result.GetName(), err)
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "mount refresh interval of virtual instance '%s' set to %s\n",
+					result.GetName(), interval)
+			}
+
 			if err = waitUntilVIActive(rs, cmd, result.GetId()); err != nil {
 				return err
 			}
@@ -55,12 +87,9 @@ func newCreateVirtualInstanceCmd() *cobra.Command {
 		},
 	}
 
-	// TODO uncomment after go client supports setting it
-	//cmd.Flags().StringP(flag.Description, "d", "", "virtual instance description")
-	cmd.Flags().Bool(flag.Wait, false, "wait until virtual instance is active")
-	cmd.Flags().String(flag.Size, "", "virtual instance size")
+	addVirtualInstanceFlags(&cmd)
+	addWaitFlags(&cmd, "virtual instance is active")
 	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.Size)
-	// TODO completion of sizes
 
 	return &cmd
 }
@@ -76,7 +105,15 @@ func newUpdateVirtualInstanceCmd() *cobra.Command {
 		ValidArgsFunction: completion.VirtualInstance(Version),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
-			size, _ := cmd.Flags().GetString(flag.Size)
+
+			options, err := virtualInstanceOptions(cmd)
+			if err != nil {
+				return err
+			}
+			refresh := cmd.Flags().Changed(flag.MountRefreshInterval)
+			if len(options) == 0 && !refresh {
+				return fmt.Errorf("nothing to update, specify at least one of the virtual instance settings")
+			}
 
 			rs, err := config.Client(cmd, Version)
 			if err != nil {
@@ -88,18 +125,31 @@ func newUpdateVirtualInstanceCmd() *cobra.Command {
 				return err
 			}
 
-			logger.Info("setting option to", "size", size)
-			var options = []option.VirtualInstanceOption{
-				option.WithVirtualInstanceSize(option.VirtualInstanceSize(size)),
+			name := args[0]
+			if len(options) > 0 {
+				result, err := rs.UpdateVirtualInstance(ctx, id, options...)
+				if err != nil {
+					return err
+				}
+				name = result.GetName()
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "virtual instance '%s' updated\n", name)
 			}
 
-			result, err := rs.UpdateVirtualInstance(ctx, id, options...)
-			if err != nil {
-				return err
+			// the mount refresh interval is set separately, once the other settings are updated
+			if refresh {
+				interval, _ := cmd.Flags().GetDuration(flag.MountRefreshInterval)
+				if err = setMountRefreshInterval(ctx, rs, id, interval); err != nil {
+					if len(options) > 0 {
+						return fmt.Errorf("virtual instance '%s' was updated, but setting the mount refresh interval failed: %w",
+							name, err)
+					}
+					return fmt.Errorf("failed to set the mount refresh interval: %w", err)
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "mount refresh interval of virtual instance '%s' set to %s\n",
+					name, interval)
 			}
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "virtual instance '%s' updated\n", result.GetName())
 
-			if err = waitUntilVIActive(rs, cmd, result.GetId()); err != nil {
+			if err = waitUntilVIActive(rs, cmd, id); err != nil {
 				return err
 			}
 
@@ -107,12 +157,8 @@ func newUpdateVirtualInstanceCmd() *cobra.Command {
 		},
 	}
 
-	// TODO uncomment after go client supports setting it
-	//cmd.Flags().StringP(flag.Description, "d", "", "virtual instance description")
-	cmd.Flags().String(flag.Size, "", "virtual instance size")
-	cmd.Flags().Bool(flag.Wait, false, "wait until virtual instance is active")
-	_ = cobra.MarkFlagRequired(cmd.Flags(), flag.Size)
-	// TODO completion of sizes
+	addVirtualInstanceFlags(&cmd)
+	addWaitFlags(&cmd, "virtual instance is active")
 
 	return &cmd
 }
@@ -212,6 +258,16 @@ func newDeleteVirtualInstanceCmd() *cobra.Command {
 				return err
 			}
 
+			vi, err := rs.GetVirtualInstance(ctx, id)
+			if err != nil {
+				return err
+			}
+
+			item := fmt.Sprintf("virtual instance %s (%s, %s)", vi.GetName(), id, vi.GetCurrentSize())
+			if ok, err := confirmDelete(cmd, []string{item}); err != nil || !ok {
+				return err
+			}
+
 			result, err := rs.DeleteVirtualInstance(ctx, id)
 			if err != nil {
 				return err
@@ -223,6 +279,8 @@ func newDeleteVirtualInstanceCmd() *cobra.Command {
 		},
 	}
 
+	addDeleteFlags(&cmd)
+
 	return &cmd
 }
 
@@ -298,23 +356,108 @@ func newResumeVirtualInstanceCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().Bool(flag.Wait, false, "wait until virtual instance is ready")
+	addWaitFlags(&cmd, "virtual instance is active")
 
 	return &cmd
 }
 
 func waitUntilVIActive(rs *rockset.RockClient, cmd *cobra.Command, vID string) error {
-	wait, err := cmd.Flags().GetBool(flag.Wait)
-	if err != nil {
-		return err
+	return waitFor(cmd.Context(), cmd, wait.Target{
+		Resource: fmt.Sprintf("virtual instance %s", vID),
+		Ready:    []string{option.VirtualInstanceActive.String()},
+		Failed:   []string{option.VirtualInstanceDeleted.String()},
+		State: func(ctx context.Context) (string, error) {
+			vi, err := rs.GetVirtualInstance(ctx, vID)
+			return vi.GetState(), err
+		},
+	})
+}
+
+func addVirtualInstanceFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP(flag.Description, "d", "", "virtual instance description")
+	cmd.Flags().String(flag.Size, "", "virtual instance size")
+	_ = cmd.RegisterFlagCompletionFunc(flag.Size, virtualInstanceSizeCompletion)
+	cmd.Flags().Duration(flag.AutoSuspend, 0,
+		fmt.Sprintf("suspend the virtual instance after this long without queries, at least %s", minAutoSuspend))
+	cmd.Flags().Duration(flag.MountRefreshInterval, 0, "how often mounted collections are refreshed, a positive whole number of seconds")
+	cmd.Flags().Bool(flag.RemountOnResume, false, "remount the collections when the virtual instance is resumed")
+}
+
+// virtualInstanceOptions returns the options for the virtual instance flags which have been set
+func virtualInstanceOptions(cmd *cobra.Command) ([]option.VirtualInstanceOption, error) {
+	var options []option.VirtualInstanceOption
+
+	if cmd.Flags().Changed(flag.Size) {
+		s, _ := cmd.Flags().GetString(flag.Size)
+		size, err := ParseVirtualInstanceSize(s)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, option.WithVirtualInstanceSize(size))
+	}
+
+	if cmd.Flags().Changed(flag.Description) {
+		desc, _ := cmd.Flags().GetString(flag.Description)
+		options = append(options, option.WithVirtualInstanceDescription(desc))
+	}
+
+	if cmd.Flags().Changed(flag.AutoSuspend) {
+		d, _ := cmd.Flags().GetDuration(flag.AutoSuspend)
+		if d < minAutoSuspend {
+			return nil, fmt.Errorf("--%s must be at least %s", flag.AutoSuspend, minAutoSuspend)
+		}
+		options = append(options, option.WithAutoSuspend(d))
+	}
+
+	if cmd.Flags().Changed(flag.MountRefreshInterval) {
+		d, _ := cmd.Flags().GetDuration(flag.MountRefreshInterval)
+		if d <= 0 || d%time.Second != 0 {
+			return nil, fmt.Errorf("--%s must be a positive whole number of seconds", flag.MountRefreshInterval)
+		}
+	}
+
+	if cmd.Flags().Changed(flag.RemountOnResume) {
+		remount, _ := cmd.Flags().GetBool(flag.RemountOnResume)
+		options = append(options, option.WithRemountOnResume(remount))
 	}
-	if wait {
-		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "waiting for virtual instance to be ready...\n")
 
-		if err := rs.Wait.UntilVirtualInstanceActive(cmd.Context(), vID); err != nil {
-			return fmt.Errorf("failed to wait for %s to be active: %v", vID, err)
+	return options, nil
+}
+
+// ParseVirtualInstanceSize validates the virtual instance size, ignoring case
+func ParseVirtualInstanceSize(s string) (option.VirtualInstanceSize, error) {
+	for _, size := range virtualInstanceSizes {
+		if strings.EqualFold(s, size.String()) {
+			return size, nil
 		}
 	}
 
-	return nil
+	list := make([]string, len(virtualInstanceSizes))
+	for i, size := range virtualInstanceSizes {
+		list[i] = size.String()
+	}
+
+	return "", fmt.Errorf("invalid virtual instance size '%s', must be one of: %s", s, strings.Join(list, ", "))
+}
+
+func virtualInstanceSizeCompletion(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	list := make([]string, len(virtualInstanceSizes))
+	for i, size := range virtualInstanceSizes {
+		list[i] = size.String()
+	}
+
+	return list, cobra.ShellCompDirectiveNoFileComp
+}
+
+// setMountRefreshInterval sets the mount refresh interval using the openapi client,
+// as the go client doesn't have an option for it
+func setMountRefreshInterval(ctx context.Context, rs *rockset.RockClient, id string, interval time.Duration) error {
+	req := openapi.NewUpdateVirtualInstanceRequest()
+	req.SetMountRefreshIntervalSeconds(int32(interval.Seconds()))
+
+	return rs.Retry(ctx, func() error {
+		_, httpResp, err := rs.VirtualInstancesApi.SetVirtualInstance(ctx, id).Body(*req).Execute()
+
+		return rockerr.NewWithStatusCode(err, httpResp)
+	})
 }
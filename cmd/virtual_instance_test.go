@@ -0,0 +1,24 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/rockset/rockset-go-client/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/cli/cmd"
+)
+
+func TestParseVirtualInstanceSize(t *testing.T) {
+	size, err := cmd.ParseVirtualInstanceSize("large")
+	require.NoError(t, err)
+	assert.Equal(t, option.SizeLarge, size)
+
+	size, err = cmd.ParseVirtualInstanceSize("XLARGE2")
+	require.NoError(t, err)
+	assert.Equal(t, option.SizeXLarge2, size)
+
+	_, err = cmd.ParseVirtualInstanceSize("HUGE")
+	assert.ErrorContains(t, err, "must be one of: FREE, NANO")
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rockset/cli/flag"
+	"github.com/rockset/cli/wait"
+)
+
+// addWaitFlags adds the --wait and --timeout flags, where what describes the state waited for
+func addWaitFlags(cmd *cobra.Command, what string) {
+	cmd.Flags().Bool(flag.Wait, false, "wait until "+what)
+	cmd.Flags().Duration(flag.Timeout, 0, "wait at most this long until "+what+", implies --wait")
+}
+
+// waitFor waits for the target if --wait or --timeout was used, showing a spinner when the output is a terminal
+func waitFor(ctx context.Context, cmd *cobra.Command, target wait.Target) error {
+	enabled, _ := cmd.Flags().GetBool(flag.Wait)
+	timeout, _ := cmd.Flags().GetDuration(flag.Timeout)
+	if !enabled && timeout == 0 {
+		return nil
+	}
+
+	out := cmd.OutOrStdout()
+	w := wait.Waiter{
+		Out:         out,
+		Interactive: isTerminal(out),
+		Timeout:     timeout,
+	}
+
+	return w.Until(ctx, target)
+}
+
+// waitUsing waits using one of the go client's waiters if --wait or --timeout was used, and like waitFor
+// returns wait.ErrTimeout if the timeout is reached
+func waitUsing(ctx context.Context, cmd *cobra.Command, what string, fn func(ctx context.Context) error) error {
+	enabled, _ := cmd.Flags().GetBool(flag.Wait)
+	timeout, _ := cmd.Flags().GetDuration(flag.Timeout)
+	if !enabled && timeout == 0 {
+		return nil
+	}
+
+	w := wait.Waiter{Out: cmd.OutOrStdout(), Timeout: timeout}
+
+	return w.Using(ctx, what, fn)
+}
@@ -1,12 +1,17 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
+	"github.com/rockset/rockset-go-client"
+	rockerr "github.com/rockset/rockset-go-client/errors"
 	"github.com/rockset/rockset-go-client/openapi"
 	"github.com/rockset/rockset-go-client/option"
 	"github.com/spf13/cobra"
 
+	"github.com/rockset/cli/cascade"
 	"github.com/rockset/cli/completion"
 	"github.com/rockset/cli/config"
 	"github.com/rockset/cli/flag"
@@ -53,95 +58,72 @@ func newCreateWorkspaceCmd() *cobra.Command {
 
 func newDeleteWorkspaceCmd() *cobra.Command {
 	cmd := cobra.Command{
-		Use:               "workspace NAME",
-		Aliases:           []string{"ws"},
-		Short:             "delete workspace",
-		Long:              "delete Rockset workspace",
+		Use:     "workspace NAME",
+		Aliases: []string{"ws"},
+		Short:   "delete workspace",
+		Long: `delete Rockset workspace
+
+With --recurse everything in the workspace is deleted first, query lambdas, views and aliases before the
+collections they depend on. The resources are deleted concurrently, and a resource which fails to be deleted
+doesn't stop the others. The workspace is only deleted if all resources in it were, and as the command only
+deletes what is left, it can be run again to finish deleting a partially deleted workspace.`,
+		Example: `	## delete the workspace and everything in it, without asking for confirmation
+	rockset delete workspace staging --recurse --yes
+
+	## show what would be deleted
+	rockset delete workspace staging --recurse --dry-run`,
 		Args:              cobra.ExactArgs(1),
 		Annotations:       group("workspace"),
 		ValidArgsFunction: completion.Workspace(Version),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			ws := args[0]
-			recurse, _ := cmd.Flags().GetBool("recurse")
+			recurse, _ := cmd.Flags().GetBool(flag.Recurse)
+			concurrency, _ := cmd.Flags().GetInt(flag.Concurrency)
+			out := cmd.OutOrStdout()
 
 			rs, err := config.Client(cmd, Version)
 			if err != nil {
 				return err
 			}
 
+			var phases [][]cascade.Step
 			if recurse {
-				// TODO these should be moved into the go client
-
-				collections, err := rs.ListCollections(ctx, option.WithWorkspace(ws))
-				if err != nil {
-					return err
-				}
-				for _, collection := range collections {
-					if err = rs.DeleteCollection(ctx, ws, collection.GetName()); err != nil {
-						return err
-					}
-
-					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "deleted collection %s\n", collection.GetName())
-				}
-
-				views, err := rs.ListViews(ctx, option.WithViewWorkspace(ws))
-				if err != nil {
-					return err
-				}
-				for _, view := range views {
-					if err = rs.DeleteView(ctx, ws, view.GetName()); err != nil {
-						return err
-					}
-
-					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "deleted view %s\n", view.GetName())
-				}
-
-				qls, err := rs.ListQueryLambdas(ctx, option.WithQueryLambdaWorkspace(ws))
-				if err != nil {
-					return err
-				}
-				for _, ql := range qls {
-					if err = rs.DeleteQueryLambda(ctx, ws, ql.GetName()); err != nil {
-						return err
-					}
-
-					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "deleted query lambda %s\n", ql.GetName())
-				}
+				warnBrokenWorkspaceDependents(ctx, cmd, rs, ws)
 
-				aliases, err := rs.ListAliases(ctx, option.WithAliasWorkspace(ws))
-				if err != nil {
+				if phases, err = workspaceCascade(ctx, rs, ws); err != nil {
 					return err
 				}
-				for _, alias := range aliases {
-					if err = rs.DeleteAlias(ctx, ws, alias.GetName()); err != nil {
-						return err
-					}
+			}
 
-					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "deleted alias %s\n", alias.GetName())
+			var items []string
+			for _, steps := range phases {
+				for _, s := range steps {
+					items = append(items, s.Resource)
 				}
+			}
+			items = append(items, "workspace "+ws)
 
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "waiting for all resources to be gone...\n")
-
-				for _, collection := range collections {
-					if err = rs.Wait.UntilCollectionGone(ctx, ws, collection.GetName()); err != nil {
-						return err
-					}
-				}
+			if ok, err := confirmDelete(cmd, items); err != nil || !ok {
+				return err
+			}
 
-				for _, view := range views {
-					if err = rs.Wait.UntilViewGone(ctx, ws, view.GetName()); err != nil {
-						return err
-					}
+			results := cascade.Run(ctx, concurrency, phases, func(done, total int, r cascade.Result) {
+				if r.Err != nil {
+					_, _ = fmt.Fprintf(out, "[%d/%d] failed to delete %s: %v\n", done, total, r.Resource, r.Err)
+				} else {
+					_, _ = fmt.Fprintf(out, "[%d/%d] deleted %s\n", done, total, r.Resource)
 				}
+			})
 
-				for _, alias := range aliases {
-					if err = rs.Wait.UntilAliasGone(ctx, ws, alias.GetName()); err != nil {
-						return err
-					}
+			if failed := cascade.Failed(results); len(failed) > 0 {
+				_, _ = fmt.Fprintf(out, "deleted %d of %s, failed to delete:\n", len(results)-len(failed),
+					plural(len(results), "resource"))
+				for _, r := range failed {
+					_, _ = fmt.Fprintf(out, "  %s: %v\n", r.Resource, r.Err)
 				}
-
-				// TODO wait until QLs are gone (missing support in go client)
+				return fmt.Errorf("workspace '%s' not deleted as %s failed, run the command again to retry",
+					ws, plural(len(failed), "resource"))
 			}
 
 			err = rs.DeleteWorkspace(ctx, ws)
@@ -149,16 +131,116 @@ func newDeleteWorkspaceCmd() *cobra.Command {
 				return err
 			}
 
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "workspace '%s' deleted\n", ws)
+			_, _ = fmt.Fprintf(out, "workspace '%s' deleted\n", ws)
 			return nil
 		},
 	}
 
-	cmd.Flags().Bool("recurse", false, "recursively delete everything in the workspace, i.e. collections, query lambdas and views")
+	cmd.Flags().Bool(flag.Recurse, false, "recursively delete everything in the workspace, i.e. collections, query lambdas and views")
+	cmd.Flags().Int(flag.Concurrency, 8, "number of resources to delete concurrently")
+	addDeleteFlags(&cmd)
 
 	return &cmd
 }
 
+// collectionDeleted is the status of a collection which is being deleted
+const collectionDeleted = "DELETED"
+
+// workspaceCascade returns the steps to delete everything in the workspace, where the first phase deletes the query
+// lambdas, views and aliases, and the second phase the collections, as the others might depend on them.
+// Resources which are already gone when they are deleted are skipped, so an interrupted cascade can be resumed.
+func workspaceCascade(ctx context.Context, rs *rockset.RockClient, ws string) ([][]cascade.Step, error) {
+	qls, err := rs.ListQueryLambdas(ctx, option.WithQueryLambdaWorkspace(ws))
+	if err != nil {
+		return nil, err
+	}
+	views, err := rs.ListViews(ctx, option.WithViewWorkspace(ws))
+	if err != nil {
+		return nil, err
+	}
+	aliases, err := rs.ListAliases(ctx, option.WithAliasWorkspace(ws))
+	if err != nil {
+		return nil, err
+	}
+	collections, err := rs.ListCollections(ctx, option.WithWorkspace(ws))
+	if err != nil {
+		return nil, err
+	}
+
+	var dependents []cascade.Step
+	for _, ql := range qls {
+		name := ql.GetName()
+		step := cascade.Step{
+			Resource: fmt.Sprintf("query lambda %s.%s", ws, name),
+			Delete: func(ctx context.Context) error {
+				return ignoreNotFound(rs.DeleteQueryLambda(ctx, ws, name))
+			},
+		}
+		if latest, ok := ql.GetLatestVersionOk(); ok {
+			version := latest.GetVersion()
+			step.Gone = func(ctx context.Context) error {
+				return rs.Wait.UntilQueryLambdaVersionGone(ctx, ws, name, version)
+			}
+		}
+		dependents = append(dependents, step)
+	}
+	for _, view := range views {
+		name := view.GetName()
+		dependents = append(dependents, cascade.Step{
+			Resource: fmt.Sprintf("view %s.%s", ws, name),
+			Delete: func(ctx context.Context) error {
+				return ignoreNotFound(rs.DeleteView(ctx, ws, name))
+			},
+			Gone: func(ctx context.Context) error {
+				return rs.Wait.UntilViewGone(ctx, ws, name)
+			},
+		})
+	}
+	for _, alias := range aliases {
+		name := alias.GetName()
+		dependents = append(dependents, cascade.Step{
+			Resource: fmt.Sprintf("alias %s.%s", ws, name),
+			Delete: func(ctx context.Context) error {
+				return ignoreNotFound(rs.DeleteAlias(ctx, ws, name))
+			},
+			Gone: func(ctx context.Context) error {
+				return rs.Wait.UntilAliasGone(ctx, ws, name)
+			},
+		})
+	}
+
+	var collectionSteps []cascade.Step
+	for _, c := range collections {
+		name := c.GetName()
+		// a collection which is already being deleted only needs to be waited for
+		deleting := c.GetStatus() == collectionDeleted
+		collectionSteps = append(collectionSteps, cascade.Step{
+			Resource: fmt.Sprintf("collection %s.%s", ws, name),
+			Delete: func(ctx context.Context) error {
+				if deleting {
+					return nil
+				}
+				return ignoreNotFound(rs.DeleteCollection(ctx, ws, name))
+			},
+			Gone: func(ctx context.Context) error {
+				return rs.Wait.UntilCollectionGone(ctx, ws, name)
+			},
+		})
+	}
+
+	return [][]cascade.Step{dependents, collectionSteps}, nil
+}
+
+// ignoreNotFound returns nil if the error is because the resource doesn't exist
+func ignoreNotFound(err error) error {
+	var re rockerr.Error
+	if errors.As(err, &re) && re.IsNotFoundError() {
+		return nil
+	}
+
+	return err
+}
+
 func NewGetWorkspaceCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:               "workspace NAME",
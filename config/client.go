@@ -11,6 +11,30 @@ import (
 )
 
 func Client(cmd *cobra.Command, version string) (*rockset.RockClient, error) {
+	override, _ := cmd.Flags().GetString(flag.Context)
+	if override != "" {
+		slog.Debug("using override", "name", override)
+	}
+
+	return ContextClient(override, version)
+}
+
+// ContextName returns the name of the context used by Client
+func ContextName(cmd *cobra.Command) (string, error) {
+	if override, _ := cmd.Flags().GetString(flag.Context); override != "" {
+		return override, nil
+	}
+
+	cfg, err := Load()
+	if err != nil && !errors.Is(err, NotFoundErr) {
+		return "", err
+	}
+
+	return cfg.Current, nil
+}
+
+// ContextClient creates a client for the named context, or the current context if name is empty
+func ContextClient(name, version string) (*rockset.RockClient, error) {
 	// load from config, ok if none is found
 	cfg, err := Load()
 	if err != nil {
@@ -19,16 +43,11 @@ func Client(cmd *cobra.Command, version string) (*rockset.RockClient, error) {
 		}
 	}
 
-	override, _ := cmd.Flags().GetString(flag.Context)
-	if override != "" {
-		slog.Debug("using override", "name", override)
-	}
-
 	var options = []rockset.RockOption{
 		rockset.WithUserAgent("rockset-go-cli/" + version),
 	}
 
-	opts, err := cfg.AsOptions(override)
+	opts, err := cfg.AsOptions(name)
 	if err != nil {
 		return nil, err
 	}
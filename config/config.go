@@ -15,8 +15,10 @@ import (
 )
 
 const (
-	FileName        = "config.yaml"
-	HistoryFileName = "cli.hist"
+	FileName         = "config.yaml"
+	HistoryFileName  = "cli.hist"
+	ScheduleFileName = "schedules.yaml"
+	JournalFileName  = "alias-journal.yaml"
 
 	Usw2a1 = "usw2a1"
 	Use1a1 = "use1a1"
@@ -161,6 +163,14 @@ func HistoryFile() (string, error) {
 	return rocksetConfigDir(HistoryFileName)
 }
 
+func ScheduleFile() (string, error) {
+	return rocksetConfigDir(ScheduleFileName)
+}
+
+func JournalFile() (string, error) {
+	return rocksetConfigDir(JournalFileName)
+}
+
 func rocksetConfigDir(name string) (string, error) {
 	home, err := homedir.Dir()
 	if err != nil {
@@ -0,0 +1,106 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+type Op int
+
+const (
+	Equal Op = iota
+	Added
+	Removed
+)
+
+func (o Op) Prefix() string {
+	switch o {
+	case Added:
+		return "+"
+	case Removed:
+		return "-"
+	default:
+		return " "
+	}
+}
+
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// Lines computes a line based diff between a and b using the longest common subsequence
+func Lines(a, b []string) []Line {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var result []Line
+	var i, j int
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			result = append(result, Line{Op: Equal, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			result = append(result, Line{Op: Removed, Text: a[i]})
+			i++
+		default:
+			result = append(result, Line{Op: Added, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		result = append(result, Line{Op: Removed, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		result = append(result, Line{Op: Added, Text: b[j]})
+	}
+
+	return result
+}
+
+// Text splits a and b into lines and diffs them
+func Text(a, b string) []Line {
+	return Lines(splitLines(a), splitLines(b))
+}
+
+// Changed returns true if any line was added or removed
+func Changed(lines []Line) bool {
+	for _, l := range lines {
+		if l.Op != Equal {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Write writes the diff to out, prefixing each line with +, - or a space
+func Write(out io.Writer, lines []Line) {
+	for _, l := range lines {
+		_, _ = fmt.Fprintf(out, "%s %s\n", l.Op.Prefix(), l.Text)
+	}
+}
+
+func splitLines(s string) []string {
+	s = strings.TrimRight(s, "\n")
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(s, "\n")
+}
@@ -0,0 +1,41 @@
+package diff_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rockset/cli/diff"
+)
+
+func TestText(t *testing.T) {
+	lines := diff.Text("SELECT *\nFROM a\nWHERE x = 1\n", "SELECT *\nFROM b\nWHERE x = 1")
+
+	assert.Equal(t, []diff.Line{
+		{Op: diff.Equal, Text: "SELECT *"},
+		{Op: diff.Removed, Text: "FROM a"},
+		{Op: diff.Added, Text: "FROM b"},
+		{Op: diff.Equal, Text: "WHERE x = 1"},
+	}, lines)
+	assert.True(t, diff.Changed(lines))
+}
+
+func TestText_identical(t *testing.T) {
+	lines := diff.Text("SELECT 1", "SELECT 1\n")
+
+	assert.False(t, diff.Changed(lines))
+}
+
+func TestText_empty(t *testing.T) {
+	lines := diff.Text("", "SELECT 1")
+
+	assert.Equal(t, []diff.Line{{Op: diff.Added, Text: "SELECT 1"}}, lines)
+}
+
+func TestWrite(t *testing.T) {
+	var out bytes.Buffer
+	diff.Write(&out, diff.Text("a\nb", "a\nc"))
+
+	assert.Equal(t, "  a\n- b\n+ c\n", out.String())
+}
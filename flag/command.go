@@ -1,35 +1,107 @@
 package flag
 
 const (
-	Async                = "async"
-	Bucket               = "bucket"
-	Compression          = "compression"
-	Collection           = "collection"
-	Cursor               = "cursor"
-	Dataset              = "dataset"
-	Description          = "description"
-	Docs                 = "docs"
-	Email                = "email"
-	File                 = "file"
-	Force                = "force"
-	IngestTransformation = "ingest-transformation"
-	Integration          = "integration"
-	Offset               = "offset"
-	Pattern              = "pattern"
-	Region               = "region"
-	Retention            = "retention"
-	Role                 = "role"
-	RoleARN              = "role-arn"
-	Size                 = "size"
-	SQL                  = "sql"
-	State                = "state"
-	Tag                  = "tag"
-	Tags                 = "tags"
-	Validate             = "validate"
-	VI                   = "vi"
-	Version              = "version"
-	Versions             = "versions"
-	Wait                 = "wait"
-	Workspace            = "workspace"
-	WorkspaceShort       = "W"
+	APIKey                   = "apikey"
+	As                       = "as"
+	Async                    = "async"
+	AutoSuspend              = "auto-suspend"
+	BootstrapServers         = "bootstrap-servers"
+	Bucket                   = "bucket"
+	Cases                    = "cases"
+	CompareCounts            = "compare-counts"
+	Compression              = "compression"
+	Concurrency              = "concurrency"
+	Collection               = "collection"
+	ConsumerGroup            = "consumer-group"
+	Container                = "container"
+	CredentialsFile          = "credentials-file"
+	CSVColumns               = "csv-columns"
+	CSVEscape                = "csv-escape"
+	CSVHeader                = "csv-header"
+	CSVQuote                 = "csv-quote"
+	CSVSeparator             = "csv-separator"
+	Cursor                   = "cursor"
+	Database                 = "database"
+	Dataset                  = "dataset"
+	Definition               = "definition"
+	Depth                    = "depth"
+	Description              = "description"
+	DMSPrimaryKey            = "dms-primary-key"
+	Docs                     = "docs"
+	DryRun                   = "dry-run"
+	Duration                 = "duration"
+	Email                    = "email"
+	Encoding                 = "encoding"
+	File                     = "file"
+	Force                    = "force"
+	GraphFormat              = "graph-format"
+	Horizon                  = "horizon"
+	IngestTransformation     = "ingest-transformation"
+	IngestTransformationFile = "ingest-transformation-file"
+	Integration              = "integration"
+	Interval                 = "interval"
+	JUnit                    = "junit"
+	Key                      = "key"
+	Lambda                   = "lambda"
+	MaxCountDiff             = "max-count-diff"
+	MaxDiffs                 = "max-diffs"
+	MountRefreshInterval     = "mount-refresh-interval"
+	Offset                   = "offset"
+	Ordered                  = "ordered"
+	Param                    = "param"
+	Pattern                  = "pattern"
+	Prefix                   = "prefix"
+	PrivilegeCluster         = "privilege-cluster"
+	RCU                      = "rcu"
+	Recurse                  = "recurse"
+	Region                   = "region"
+	Remove                   = "remove"
+	RemountOnResume          = "remount-on-resume"
+	Requests                 = "requests"
+	Retention                = "retention"
+	RetrieveFullDocument     = "retrieve-full-document"
+	Role                     = "role"
+	RoleARN                  = "role-arn"
+	Rollback                 = "rollback"
+	S3ExportBucket           = "s3-export-bucket"
+	S3ExportPath             = "s3-export-path"
+	Sample                   = "sample"
+	Schema                   = "schema"
+	SchemaRegistryURL        = "schema-registry-url"
+	ServiceAccountKeyFile    = "service-account-key-file"
+	Size                     = "size"
+	SortBy                   = "sort-by"
+	SourceFormat             = "source-format"
+	SQL                      = "sql"
+	StartingOffset           = "starting-offset"
+	State                    = "state"
+	Stream                   = "stream"
+	Table                    = "table"
+	Tag                      = "tag"
+	Tags                     = "tags"
+	Timeout                  = "timeout"
+	To                       = "to"
+	ToContext                = "to-context"
+	Topic                    = "topic"
+	UnusedSince              = "unused-since"
+	URL                      = "url"
+	User                     = "user"
+	Username                 = "username"
+	UserRole                 = "user-role"
+	UseScanAPI               = "use-scan-api"
+	V3                       = "v3"
+	Validate                 = "validate"
+	VI                       = "vi"
+	Version                  = "version"
+	Versions                 = "versions"
+	Wait                     = "wait"
+	Watch                    = "watch"
+	Warehouse                = "warehouse"
+	Workspace                = "workspace"
+	WorkspaceShort           = "W"
+	XMLAttributePrefix       = "xml-attribute-prefix"
+	XMLDocTag                = "xml-doc-tag"
+	XMLRootTag               = "xml-root-tag"
+	XMLValueTag              = "xml-value-tag"
+	Yes                      = "yes"
 )
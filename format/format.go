@@ -95,6 +95,10 @@ func defaultSelectorFor(f any) (DefaultSelector, error) {
 		return QueryLambdaVersionDefaultSelector, nil
 	case openapi.QueryLambdaTag:
 		return QueryLambdaTagDefaultSelector, nil
+	case QueryLambdaUsage:
+		return QueryLambdaUsageDefaultSelector, nil
+	case QueryLambdaVersionUsage:
+		return QueryLambdaVersionUsageDefaultSelector, nil
 	case openapi.View:
 		return ViewDefaultSelector, nil
 	case openapi.VirtualInstance:
@@ -116,6 +116,17 @@ func TestFormatter(t *testing.T) {
 			},
 			s: "name,desc,pme,when\n",
 		},
+		{
+			i: format.QueryLambdaVersionUsage{
+				Workspace: "ws",
+				Name:      "name",
+				Version:   "version",
+				Tags:      []string{"latest", "prod"},
+				State:     "ACTIVE",
+				Stale:     true,
+			},
+			s: "ws,name,version,\"latest, prod\",ACTIVE,true,false,never,,never,\n",
+		},
 	}
 
 	for _, tc := range testCases {
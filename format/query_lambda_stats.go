@@ -0,0 +1,108 @@
+package format
+
+// QueryLambdaUsage is the execution statistics summarized for all versions of a query lambda
+type QueryLambdaUsage struct {
+	Workspace        string `json:"workspace"`
+	Name             string `json:"name"`
+	Versions         int64  `json:"versions"`
+	ExecutedVersions int64  `json:"executed_versions"`
+	StaleVersions    int64  `json:"stale_versions"`
+	FailingVersions  int64  `json:"failing_versions"`
+	LastExecuted     int64  `json:"last_executed"`
+	LastExecutedBy   string `json:"last_executed_by"`
+	LastError        int64  `json:"last_error"`
+	LastErrorMessage string `json:"last_error_message"`
+}
+
+// QueryLambdaVersionUsage is the execution statistics for a single query lambda version
+type QueryLambdaVersionUsage struct {
+	Workspace        string   `json:"workspace"`
+	Name             string   `json:"name"`
+	Version          string   `json:"version"`
+	Tags             []string `json:"tags"`
+	State            string   `json:"state"`
+	Stale            bool     `json:"stale"`
+	Failing          bool     `json:"failing"`
+	LastExecuted     int64    `json:"last_executed"`
+	LastExecutedBy   string   `json:"last_executed_by"`
+	LastError        int64    `json:"last_error"`
+	LastErrorMessage string   `json:"last_error_message"`
+}
+
+func (u QueryLambdaUsage) GetWorkspace() string        { return u.Workspace }
+func (u QueryLambdaUsage) GetName() string             { return u.Name }
+func (u QueryLambdaVersionUsage) GetWorkspace() string { return u.Workspace }
+func (u QueryLambdaVersionUsage) GetName() string      { return u.Name }
+
+var QueryLambdaUsageDefaultSelector = DefaultSelector{
+	Normal: []FieldSelection{
+		NewFieldSelection("Workspace", "workspace"),
+		NewFieldSelection("Name", "name"),
+		NewFieldSelection("Versions", "versions"),
+		NewFieldSelection("Executed", "executed_versions"),
+		NewFieldSelection("Never Executed", "stale_versions"),
+		NewFieldSelection("Failing", "failing_versions"),
+		{
+			ColumnName:     "Last Executed",
+			Path:           []PathElem{{FieldName: "last_executed"}},
+			FieldFormatter: TimeSinceFormatter{},
+		},
+	},
+	Wide: []FieldSelection{
+		NewFieldSelection("Workspace", "workspace"),
+		NewFieldSelection("Name", "name"),
+		NewFieldSelection("Versions", "versions"),
+		NewFieldSelection("Executed", "executed_versions"),
+		NewFieldSelection("Never Executed", "stale_versions"),
+		NewFieldSelection("Failing", "failing_versions"),
+		{
+			ColumnName:     "Last Executed",
+			Path:           []PathElem{{FieldName: "last_executed"}},
+			FieldFormatter: TimeSinceFormatter{},
+		},
+		NewFieldSelection("Last Executed By", "last_executed_by"),
+		{
+			ColumnName:     "Last Error",
+			Path:           []PathElem{{FieldName: "last_error"}},
+			FieldFormatter: TimeSinceFormatter{},
+		},
+		NewFieldSelection("Last Error Message", "last_error_message"),
+	},
+}
+
+var QueryLambdaVersionUsageDefaultSelector = DefaultSelector{
+	Normal: []FieldSelection{
+		NewFieldSelection("Workspace", "workspace"),
+		NewFieldSelection("Name", "name"),
+		NewFieldSelection("Version", "version"),
+		NewFieldSelection("Tags", "tags"),
+		NewFieldSelection("Never Executed", "stale"),
+		NewFieldSelection("Failing", "failing"),
+		{
+			ColumnName:     "Last Executed",
+			Path:           []PathElem{{FieldName: "last_executed"}},
+			FieldFormatter: TimeSinceFormatter{},
+		},
+	},
+	Wide: []FieldSelection{
+		NewFieldSelection("Workspace", "workspace"),
+		NewFieldSelection("Name", "name"),
+		NewFieldSelection("Version", "version"),
+		NewFieldSelection("Tags", "tags"),
+		NewFieldSelection("State", "state"),
+		NewFieldSelection("Never Executed", "stale"),
+		NewFieldSelection("Failing", "failing"),
+		{
+			ColumnName:     "Last Executed",
+			Path:           []PathElem{{FieldName: "last_executed"}},
+			FieldFormatter: TimeSinceFormatter{},
+		},
+		NewFieldSelection("Last Executed By", "last_executed_by"),
+		{
+			ColumnName:     "Last Error",
+			Path:           []PathElem{{FieldName: "last_error"}},
+			FieldFormatter: TimeSinceFormatter{},
+		},
+		NewFieldSelection("Last Error Message", "last_error_message"),
+	},
+}
@@ -8,6 +8,7 @@ var ViewDefaultSelector = DefaultSelector{
 		NewFieldSelection("Name", "name"),
 		NewFieldSelection("Created By", "creator_email"),
 		NewFieldSelection("State", "state"),
+		NewFieldSelection("Depends On", "entities"),
 	},
 	Wide: []FieldSelection{
 		NewFieldSelection("Workspace", "workspace"),
@@ -16,6 +17,7 @@ var ViewDefaultSelector = DefaultSelector{
 		NewFieldSelection("Created By", "creator_email"),
 		NewFieldSelection("Created At", "created_at"),
 		NewFieldSelection("State", "state"),
+		NewFieldSelection("Depends On", "entities"),
 		NewFieldSelection("SQL", "query_sql"),
 	},
 }
@@ -16,5 +16,8 @@ var VirtualInstanceDefaultSelector = DefaultSelector{
 		NewFieldSelection("Default VI", "default_vi"),
 		NewFieldSelection("Current Size", "current_size"),
 		NewFieldSelection("Desired Size", "desired_size"),
+		NewFieldSelection("Auto Suspend Seconds", "auto_suspend_seconds"),
+		NewFieldSelection("Mount Refresh Interval Seconds", "mount_refresh_interval_seconds"),
+		NewFieldSelection("Remount On Resume", "enable_remount_on_resume"),
 	},
 }
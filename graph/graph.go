@@ -0,0 +1,316 @@
+// Package graph is the dependency graph between collections, aliases, views and query lambdas
+package graph
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+type Kind string
+
+const (
+	Collection Kind = "collection"
+	Alias      Kind = "alias"
+	View       Kind = "view"
+	Lambda     Kind = "lambda"
+)
+
+// Node is a resource in the graph
+type Node struct {
+	Kind      Kind
+	Workspace string
+	Name      string
+}
+
+// Path returns WORKSPACE.NAME
+func (n Node) Path() string {
+	return n.Workspace + "." + n.Name
+}
+
+func (n Node) String() string {
+	return fmt.Sprintf("%s %s", n.Kind, n.Path())
+}
+
+// Graph has an edge from each resource to the resources it depends on
+type Graph struct {
+	nodes map[Node]bool
+	// deps maps a node to the nodes it depends on
+	deps map[Node]map[Node]bool
+	// paths maps WORKSPACE.NAME to the alias, view or collection with that path
+	paths map[string]Node
+}
+
+func New() *Graph {
+	return &Graph{
+		nodes: make(map[Node]bool),
+		deps:  make(map[Node]map[Node]bool),
+		paths: make(map[string]Node),
+	}
+}
+
+// Add adds a node to the graph
+func (g *Graph) Add(n Node) {
+	g.nodes[n] = true
+	if n.Kind != Lambda {
+		g.paths[n.Path()] = n
+	}
+}
+
+// DependsOn records that the node depends on the resource with the path WORKSPACE.NAME,
+// which is resolved to an alias, view or collection when the graph is used, as they share the same namespace
+func (g *Graph) DependsOn(n Node, path string) {
+	g.Add(n)
+	if g.deps[n] == nil {
+		g.deps[n] = make(map[Node]bool)
+	}
+	// the kind is resolved later, as the resource might not have been added yet
+	ws, name, _ := strings.Cut(path, ".")
+	g.deps[n][Node{Workspace: ws, Name: name}] = true
+}
+
+// resolve returns the node for the unresolved dependency, which is a collection unless there is
+// an alias or view with the same path
+func (g *Graph) resolve(dep Node) Node {
+	if n, found := g.paths[dep.Path()]; found {
+		return n
+	}
+
+	return Node{Kind: Collection, Workspace: dep.Workspace, Name: dep.Name}
+}
+
+// Nodes returns all nodes, including dependencies which weren't added, sorted by path and kind
+func (g *Graph) Nodes() []Node {
+	seen := make(map[Node]bool)
+	for n := range g.nodes {
+		seen[n] = true
+	}
+	for _, deps := range g.deps {
+		for d := range deps {
+			seen[g.resolve(d)] = true
+		}
+	}
+
+	list := make([]Node, 0, len(seen))
+	for n := range seen {
+		list = append(list, n)
+	}
+	sortNodes(list)
+
+	return list
+}
+
+// InWorkspace returns the nodes in the workspace
+func (g *Graph) InWorkspace(ws string) []Node {
+	var list []Node
+	for _, n := range g.Nodes() {
+		if n.Workspace == ws {
+			list = append(list, n)
+		}
+	}
+
+	return list
+}
+
+// Dependencies returns the nodes n directly depends on
+func (g *Graph) Dependencies(n Node) []Node {
+	var list []Node
+	for d := range g.deps[n] {
+		list = append(list, g.resolve(d))
+	}
+	sortNodes(list)
+
+	return list
+}
+
+// Dependents returns the nodes which directly depend on n
+func (g *Graph) Dependents(n Node) []Node {
+	var list []Node
+	for from, deps := range g.deps {
+		for d := range deps {
+			if g.resolve(d) == n {
+				list = append(list, from)
+				break
+			}
+		}
+	}
+	sortNodes(list)
+
+	return list
+}
+
+// AllDependents returns the nodes which directly or indirectly depend on n
+func (g *Graph) AllDependents(n Node) []Node {
+	seen := map[Node]bool{n: true}
+	var list []Node
+
+	queue := []Node{n}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, d := range g.Dependents(current) {
+			if !seen[d] {
+				seen[d] = true
+				list = append(list, d)
+				queue = append(queue, d)
+			}
+		}
+	}
+	sortNodes(list)
+
+	return list
+}
+
+// Broken returns the nodes which depend on the removed nodes, but aren't removed themselves
+func (g *Graph) Broken(removed []Node) []Node {
+	gone := make(map[Node]bool)
+	for _, n := range removed {
+		gone[n] = true
+	}
+
+	seen := make(map[Node]bool)
+	var list []Node
+	for _, n := range removed {
+		for _, d := range g.AllDependents(n) {
+			if !gone[d] && !seen[d] {
+				seen[d] = true
+				list = append(list, d)
+			}
+		}
+	}
+	sortNodes(list)
+
+	return list
+}
+
+// Text writes the graph as a tree of each resource and the resources which depend on it
+func (g *Graph) Text(w io.Writer) error {
+	for _, n := range g.Nodes() {
+		if _, err := fmt.Fprintln(w, n); err != nil {
+			return err
+		}
+		if err := g.textDependents(w, n, "", map[Node]bool{n: true}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (g *Graph) textDependents(w io.Writer, n Node, prefix string, visited map[Node]bool) error {
+	dependents := g.Dependents(n)
+	for i, d := range dependents {
+		branch, indent := "├─ ", "│  "
+		if i == len(dependents)-1 {
+			branch, indent = "└─ ", "   "
+		}
+
+		suffix := ""
+		if visited[d] {
+			suffix = " (cycle)"
+		}
+		if _, err := fmt.Fprintf(w, "%s%sused by %s%s\n", prefix, branch, d, suffix); err != nil {
+			return err
+		}
+		if visited[d] {
+			continue
+		}
+
+		visited[d] = true
+		if err := g.textDependents(w, d, prefix+indent, visited); err != nil {
+			return err
+		}
+		delete(visited, d)
+	}
+
+	return nil
+}
+
+// DOT writes the graph in Graphviz DOT format, with edges pointing to the dependencies
+func (g *Graph) DOT(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("digraph rockset {\n  rankdir=LR;\n")
+	for _, n := range g.Nodes() {
+		b.WriteString(fmt.Sprintf("  %q [label=%q, shape=%s];\n", n.String(), n.Path(), dotShapes[n.Kind]))
+	}
+	for _, n := range g.Nodes() {
+		for _, d := range g.Dependencies(n) {
+			b.WriteString(fmt.Sprintf("  %q -> %q;\n", n.String(), d.String()))
+		}
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+var dotShapes = map[Kind]string{
+	Collection: "cylinder",
+	Alias:      "cds",
+	View:       "box",
+	Lambda:     "component",
+}
+
+// Mermaid writes the graph as a Mermaid flowchart, with edges pointing to the dependencies
+func (g *Graph) Mermaid(w io.Writer) error {
+	var b strings.Builder
+
+	nodes := g.Nodes()
+	ids := make(map[Node]string, len(nodes))
+	for i, n := range nodes {
+		ids[n] = fmt.Sprintf("n%d", i)
+	}
+
+	b.WriteString("flowchart LR\n")
+	for _, n := range nodes {
+		open, end := mermaidShapes[n.Kind][0], mermaidShapes[n.Kind][1]
+		b.WriteString(fmt.Sprintf("  %s%s\"%s %s\"%s\n", ids[n], open, n.Kind, n.Path(), end))
+	}
+	for _, n := range nodes {
+		for _, d := range g.Dependencies(n) {
+			b.WriteString(fmt.Sprintf("  %s --> %s\n", ids[n], ids[d]))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+var mermaidShapes = map[Kind][2]string{
+	Collection: {"[(", ")]"},
+	Alias:      {">", "]"},
+	View:       {"[", "]"},
+	Lambda:     {"[[", "]]"},
+}
+
+// sqlReference matches WORKSPACE.NAME after FROM or JOIN, where the names might be quoted
+var sqlReference = regexp.MustCompile(`(?i)\b(?:FROM|JOIN)\s+("?[\w-]+"?)\s*\.\s*("?[\w-]+"?)`)
+
+// SQLReferences returns the WORKSPACE.NAME paths the SQL selects from, which is used when the API
+// doesn't return the references, and only finds fully qualified names
+func SQLReferences(sql string) []string {
+	var list []string
+	for _, m := range sqlReference.FindAllStringSubmatch(sql, -1) {
+		path := strings.Trim(m[1], `"`) + "." + strings.Trim(m[2], `"`)
+		if !slices.Contains(list, path) {
+			list = append(list, path)
+		}
+	}
+
+	return list
+}
+
+func sortNodes(list []Node) {
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Path() != list[j].Path() {
+			return list[i].Path() < list[j].Path()
+		}
+		return list[i].Kind < list[j].Kind
+	})
+}
@@ -0,0 +1,84 @@
+package graph_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/cli/graph"
+)
+
+var (
+	movies    = graph.Node{Kind: graph.Collection, Workspace: "commons", Name: "movies"}
+	ratings   = graph.Node{Kind: graph.Collection, Workspace: "commons", Name: "ratings"}
+	current   = graph.Node{Kind: graph.Alias, Workspace: "commons", Name: "current"}
+	topMovies = graph.Node{Kind: graph.View, Workspace: "commons", Name: "top"}
+	lambda    = graph.Node{Kind: graph.Lambda, Workspace: "commons", Name: "get_top"}
+)
+
+func testGraph() *graph.Graph {
+	g := graph.New()
+	g.Add(movies)
+	g.Add(ratings)
+	g.DependsOn(current, "commons.movies")
+	g.DependsOn(topMovies, "commons.current")
+	g.DependsOn(topMovies, "commons.ratings")
+	g.DependsOn(lambda, "commons.top")
+
+	return g
+}
+
+func TestGraph(t *testing.T) {
+	g := testGraph()
+
+	assert.Equal(t, []graph.Node{current, ratings}, g.Dependencies(topMovies))
+	assert.Equal(t, []graph.Node{current}, g.Dependents(movies))
+	assert.Equal(t, []graph.Node{current, lambda, topMovies}, g.AllDependents(movies))
+	assert.Len(t, g.Nodes(), 5)
+
+	// deleting the view and the collection breaks the alias and lambda
+	assert.Equal(t, []graph.Node{current, lambda}, g.Broken([]graph.Node{movies, topMovies}))
+	assert.Empty(t, g.Broken([]graph.Node{lambda}))
+}
+
+func TestGraph_unknownDependency(t *testing.T) {
+	g := graph.New()
+	g.DependsOn(lambda, "other.events")
+
+	assert.Equal(t, []graph.Node{{Kind: graph.Collection, Workspace: "other", Name: "events"}},
+		g.Dependencies(lambda))
+}
+
+func TestGraph_output(t *testing.T) {
+	g := testGraph()
+
+	var b strings.Builder
+	require.NoError(t, g.Text(&b))
+	assert.Contains(t, b.String(), `collection commons.movies
+└─ used by alias commons.current
+   └─ used by view commons.top
+      └─ used by lambda commons.get_top
+`)
+
+	b.Reset()
+	require.NoError(t, g.DOT(&b))
+	assert.Contains(t, b.String(), `"view commons.top" -> "alias commons.current";`)
+	assert.Contains(t, b.String(), `"collection commons.movies" [label="commons.movies", shape=cylinder];`)
+
+	b.Reset()
+	require.NoError(t, g.Mermaid(&b))
+	assert.True(t, strings.HasPrefix(b.String(), "flowchart LR\n"))
+	assert.Contains(t, b.String(), `[("collection commons.movies")]`)
+}
+
+func TestSQLReferences(t *testing.T) {
+	sql := `SELECT m.title FROM commons.movies m
+JOIN "commons"."ratings" r ON m.id = r.movie_id
+LEFT JOIN commons . movies x ON true
+WHERE m.id IN (SELECT id FROM other.popular)`
+
+	assert.Equal(t, []string{"commons.movies", "commons.ratings", "other.popular"}, graph.SQLReferences(sql))
+	assert.Empty(t, graph.SQLReferences("SELECT 1"))
+}
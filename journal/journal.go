@@ -0,0 +1,99 @@
+// Package journal records alias swaps, so they can be rolled back
+package journal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Swap is an alias which was changed from one set of collections to another
+type Swap struct {
+	// Context is the name of the configuration context the alias belongs to
+	Context   string    `yaml:"context"`
+	Workspace string    `yaml:"workspace"`
+	Alias     string    `yaml:"alias"`
+	From      []string  `yaml:"from"`
+	To        []string  `yaml:"to"`
+	At        time.Time `yaml:"at"`
+}
+
+// Journal is the list of swaps, oldest first
+type Journal struct {
+	Swaps []Swap `yaml:"swaps"`
+}
+
+// Record adds a swap to the journal
+func (j *Journal) Record(s Swap) {
+	j.Swaps = append(j.Swaps, s)
+}
+
+// Last returns the most recent swap of the alias
+func (j *Journal) Last(context, workspace, alias string) (Swap, bool) {
+	if i := j.last(context, workspace, alias); i >= 0 {
+		return j.Swaps[i], true
+	}
+
+	return Swap{}, false
+}
+
+// Pop removes and returns the most recent swap of the alias, so rolling back again goes further back
+func (j *Journal) Pop(context, workspace, alias string) (Swap, bool) {
+	i := j.last(context, workspace, alias)
+	if i < 0 {
+		return Swap{}, false
+	}
+
+	s := j.Swaps[i]
+	j.Swaps = append(j.Swaps[:i], j.Swaps[i+1:]...)
+
+	return s, true
+}
+
+func (j *Journal) last(context, workspace, alias string) int {
+	for i := len(j.Swaps) - 1; i >= 0; i-- {
+		s := j.Swaps[i]
+		if s.Context == context && s.Workspace == workspace && s.Alias == alias {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Load loads the journal from file, and if the file doesn't exist, it returns an empty journal.
+func Load(file string) (Journal, error) {
+	var j Journal
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return j, nil
+		}
+		return j, fmt.Errorf("failed to read alias journal: %w", err)
+	}
+
+	if err = yaml.Unmarshal(data, &j); err != nil {
+		return j, fmt.Errorf("failed to parse alias journal in %s: %w", file, err)
+	}
+
+	return j, nil
+}
+
+// Store saves the journal in file
+func Store(file string, j Journal) error {
+	if err := os.MkdirAll(path.Dir(file), 0700); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(j)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, data, 0600)
+}
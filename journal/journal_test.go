@@ -0,0 +1,55 @@
+package journal_test
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/cli/journal"
+)
+
+func TestJournal(t *testing.T) {
+	var j journal.Journal
+	j.Record(journal.Swap{Context: "prod", Workspace: "commons", Alias: "movies", From: []string{"commons.v1"},
+		To: []string{"commons.v2"}})
+	j.Record(journal.Swap{Context: "dev", Workspace: "commons", Alias: "movies", From: []string{"commons.a"},
+		To: []string{"commons.b"}})
+	j.Record(journal.Swap{Context: "prod", Workspace: "commons", Alias: "movies", From: []string{"commons.v2"},
+		To: []string{"commons.v3"}})
+
+	s, found := j.Last("prod", "commons", "movies")
+	require.True(t, found)
+	assert.Equal(t, []string{"commons.v3"}, s.To)
+
+	s, found = j.Pop("prod", "commons", "movies")
+	require.True(t, found)
+	assert.Equal(t, []string{"commons.v2"}, s.From)
+
+	s, found = j.Pop("prod", "commons", "movies")
+	require.True(t, found)
+	assert.Equal(t, []string{"commons.v1"}, s.From)
+
+	_, found = j.Pop("prod", "commons", "movies")
+	assert.False(t, found)
+	assert.Len(t, j.Swaps, 1)
+}
+
+func TestLoadStore(t *testing.T) {
+	file := path.Join(t.TempDir(), "rockset", "journal.yaml")
+
+	j, err := journal.Load(file)
+	require.NoError(t, err)
+	assert.Empty(t, j.Swaps)
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	j.Record(journal.Swap{Context: "prod", Workspace: "commons", Alias: "movies", From: []string{"commons.v1"},
+		To: []string{"commons.v2"}, At: at})
+	require.NoError(t, journal.Store(file, j))
+
+	loaded, err := journal.Load(file)
+	require.NoError(t, err)
+	assert.Equal(t, j, loaded)
+}
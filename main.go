@@ -13,6 +13,7 @@ import (
 	"github.com/getsentry/sentry-go"
 	"github.com/rockset/cli/cmd"
 	"github.com/rockset/cli/tui"
+	"github.com/rockset/cli/wait"
 )
 
 const publicDsn = "___PUBLIC_DSN___"
@@ -67,7 +68,8 @@ func main() {
 
 	root := cmd.NewRootCmd(Version)
 	if err := root.ExecuteContext(ctx); err != nil {
-		if !errors.Is(err, context.Canceled) {
+		// can-i has already shown why the action isn't allowed
+		if !errors.Is(err, context.Canceled) && !errors.Is(err, cmd.ErrNotAllowed) {
 			// TODO allow users to override the error reporting
 			// TODO log a message that we sent the error
 			// TODO this captures usage errors too, as there is no way to distinguish them from other errors
@@ -77,6 +79,9 @@ func main() {
 			tui.ShowError(os.Stderr, dbg, err)
 		}
 
+		if errors.Is(err, wait.ErrTimeout) {
+			os.Exit(wait.ExitCodeTimeout)
+		}
 		os.Exit(1)
 	}
 
@@ -0,0 +1,287 @@
+// Package role is the YAML definition of a role and its privileges, so changes to roles can be reviewed and
+// kept in version control
+package role
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/rockset/rockset-go-client/openapi"
+	"github.com/rockset/rockset-go-client/option"
+	"gopkg.in/yaml.v3"
+)
+
+// Definition is a role with its privileges
+type Definition struct {
+	Name        string      `yaml:"name"`
+	Description string      `yaml:"description,omitempty"`
+	Privileges  []Privilege `yaml:"privileges"`
+}
+
+// Privilege allows an action, on a resource unless it is a global action, and for workspace and virtual instance
+// actions only in a cluster
+type Privilege struct {
+	Action   string `yaml:"action"`
+	Resource string `yaml:"resource,omitempty"`
+	// Cluster defaults to all clusters for workspace and virtual instance actions
+	Cluster string `yaml:"cluster,omitempty"`
+}
+
+func (p Privilege) String() string {
+	s := p.Action
+	if p.Resource != "" {
+		s += " on " + p.Resource
+	}
+	if p.Cluster != "" && p.Cluster != option.AllClusters {
+		s += " in " + p.Cluster
+	}
+
+	return s
+}
+
+// Normalize sets the default cluster, so privileges can be compared
+func (p Privilege) Normalize() Privilege {
+	if clustered(p.Action) && p.Cluster == "" {
+		p.Cluster = option.AllClusters
+	}
+
+	return p
+}
+
+// Validate checks that the action is known, and that it has a resource and cluster if it should
+func (p Privilege) Validate() error {
+	switch {
+	case p.Action == "":
+		return errors.New("privilege is missing an action")
+	case option.IsGlobalAction(p.Action):
+		if p.Resource != "" || p.Cluster != "" {
+			return fmt.Errorf("global action %s can't have a resource or cluster", p.Action)
+		}
+	case option.IsIntegrationAction(p.Action):
+		if p.Resource == "" {
+			return fmt.Errorf("integration action %s requires an integration as resource", p.Action)
+		}
+		if p.Cluster != "" {
+			return fmt.Errorf("integration action %s can't have a cluster", p.Action)
+		}
+	case clustered(p.Action):
+		if p.Resource == "" {
+			return fmt.Errorf("action %s requires a workspace or virtual instance as resource", p.Action)
+		}
+	default:
+		return fmt.Errorf("unknown action %s", p.Action)
+	}
+
+	return nil
+}
+
+// API returns the privilege as used by the REST API
+func (p Privilege) API() openapi.Privilege {
+	p = p.Normalize()
+
+	var api openapi.Privilege
+	api.Action = openapi.PtrString(p.Action)
+	if p.Resource != "" {
+		api.ResourceName = openapi.PtrString(p.Resource)
+	}
+	if p.Cluster != "" {
+		api.Cluster = openapi.PtrString(p.Cluster)
+	}
+
+	return api
+}
+
+func clustered(action string) bool {
+	return option.IsWorkspaceAction(action) || option.IsVirtualInstanceAction(action)
+}
+
+// FromAPI returns the definition of the role
+func FromAPI(r openapi.Role) Definition {
+	d := Definition{
+		Name:        r.GetRoleName(),
+		Description: r.GetDescription(),
+	}
+	for _, p := range r.Privileges {
+		d.Privileges = append(d.Privileges, Privilege{
+			Action:   p.GetAction(),
+			Resource: p.GetResourceName(),
+			Cluster:  p.GetCluster(),
+		})
+	}
+
+	return d
+}
+
+// Validate checks the name and all privileges
+func (d Definition) Validate() error {
+	if d.Name == "" {
+		return errors.New("role is missing a name")
+	}
+
+	for i, p := range d.Privileges {
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("privilege %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// Options returns the description and privileges as options for creating or updating the role
+func (d Definition) Options() []option.RoleOption {
+	return []option.RoleOption{
+		option.WithRoleDescription(d.Description),
+		func(o *option.RoleOptions) {
+			for _, p := range d.Privileges {
+				o.Privileges = append(o.Privileges, p.API())
+			}
+		},
+	}
+}
+
+// Has returns true if the role has the privilege
+func (d Definition) Has(p Privilege) bool {
+	p = p.Normalize()
+	return slices.ContainsFunc(d.Privileges, func(o Privilege) bool {
+		return o.Normalize() == p
+	})
+}
+
+// Diff returns the privileges which are only in the other role, and those which are only in this role
+func (d Definition) Diff(other Definition) (added, removed []Privilege) {
+	for _, p := range other.Privileges {
+		if !d.Has(p) {
+			added = append(added, p)
+		}
+	}
+	for _, p := range d.Privileges {
+		if !other.Has(p) {
+			removed = append(removed, p)
+		}
+	}
+
+	return added, removed
+}
+
+// allResources is used for privileges which apply to all resources
+const allResources = "*ALL*"
+
+// wildcards are the actions which allow all actions of their kind
+var wildcards = map[string]func(string) bool{
+	"ALL_GLOBAL_ACTIONS":      option.IsGlobalAction,
+	"ALL_INTEGRATION_ACTIONS": option.IsIntegrationAction,
+	"ALL_WORKSPACE_ACTIONS":   option.IsWorkspaceAction,
+	"ALL_VI_ACTIONS":          option.IsVirtualInstanceAction,
+}
+
+// Allows returns true if the privilege allows the action on the resource, which is ignored for global actions,
+// in the cluster. If the cluster is empty it is allowed in at least one cluster, see OnlyIn.
+func (p Privilege) Allows(action, resource, cluster string) bool {
+	if p.Action != action {
+		if is, found := wildcards[p.Action]; !found || !is(action) {
+			return false
+		}
+	}
+
+	if !option.IsGlobalAction(action) && !matches(p.Resource, resource) {
+		return false
+	}
+
+	return cluster == "" || matches(p.Cluster, cluster)
+}
+
+// OnlyIn returns the cluster the privilege is limited to, or an empty string if it isn't limited to one
+func (p Privilege) OnlyIn() string {
+	if p.Cluster == "" || strings.EqualFold(p.Cluster, option.AllClusters) {
+		return ""
+	}
+
+	return p.Cluster
+}
+
+func matches(granted, requested string) bool {
+	return granted == "" || strings.EqualFold(granted, allResources) || granted == requested
+}
+
+// Grant is a privilege and the role which has it
+type Grant struct {
+	Role      string
+	Privilege Privilege
+}
+
+// Grants returns the privileges of the roles which allow the action on the resource in the cluster
+func Grants(roles []Definition, action, resource, cluster string) []Grant {
+	var grants []Grant
+	for _, r := range roles {
+		for _, p := range r.Privileges {
+			if p.Allows(action, resource, cluster) {
+				grants = append(grants, Grant{Role: r.Name, Privilege: p})
+			}
+		}
+	}
+
+	return grants
+}
+
+// Load reads and validates the role definition in the file
+func Load(file string) (Definition, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return Definition{}, err
+	}
+
+	var d Definition
+	if err = yaml.Unmarshal(data, &d); err != nil {
+		return Definition{}, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+
+	if err = d.Validate(); err != nil {
+		return Definition{}, fmt.Errorf("invalid role in %s: %w", file, err)
+	}
+
+	return d, nil
+}
+
+// Write writes the role definition as YAML
+func Write(w io.Writer, d Definition) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(d); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+// Actions returns all actions known by the go client, sorted
+func Actions() []string {
+	var actions []string
+	actions = append(actions, actionNames(option.AllGlobalActions)...)
+	actions = append(actions, actionNames(option.AllIntegrationActions)...)
+	actions = append(actions, actionNames(option.AllWorkspaceActions)...)
+	actions = append(actions, actionNames(option.AllVirtualInstanceAction)...)
+	sort.Strings(actions)
+
+	return actions
+}
+
+// unknownAction is the name the go client uses for an action which is out of range
+const unknownAction = "unknown"
+
+// actionNames returns the names of the actions of one kind, which are consecutive starting with first
+func actionNames[A interface {
+	~int
+	String() string
+}](first A) []string {
+	var names []string
+	for a := first; a.String() != unknownAction; a++ {
+		names = append(names, a.String())
+	}
+
+	return names
+}
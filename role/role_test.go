@@ -0,0 +1,143 @@
+package role_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rockset/rockset-go-client/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/cli/role"
+)
+
+func TestPrivilege_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		privilege role.Privilege
+		err       string
+	}{
+		{"global", role.Privilege{Action: "LIST_WS_GLOBAL"}, ""},
+		{"global with resource", role.Privilege{Action: "LIST_WS_GLOBAL", Resource: "commons"}, "can't have a resource"},
+		{"workspace", role.Privilege{Action: "QUERY_DATA_WS", Resource: "commons", Cluster: "usw2a1"}, ""},
+		{"workspace without resource", role.Privilege{Action: "QUERY_DATA_WS"}, "requires a workspace"},
+		{"integration", role.Privilege{Action: "CREATE_COLLECTION_INTEGRATION", Resource: "s3"}, ""},
+		{"integration with cluster", role.Privilege{Action: "CREATE_COLLECTION_INTEGRATION", Resource: "s3",
+			Cluster: "usw2a1"}, "can't have a cluster"},
+		{"unknown", role.Privilege{Action: "FLY"}, "unknown action FLY"},
+		{"missing", role.Privilege{}, "missing an action"},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			err := tst.privilege.Validate()
+			if tst.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tst.err)
+			}
+		})
+	}
+}
+
+func TestActions(t *testing.T) {
+	actions := role.Actions()
+	assert.Len(t, actions, 52)
+	for _, a := range actions {
+		assert.True(t, option.IsGlobalAction(a) || option.IsIntegrationAction(a) || option.IsWorkspaceAction(a) ||
+			option.IsVirtualInstanceAction(a), a)
+	}
+	assert.Contains(t, actions, "ALL_GLOBAL_ACTIONS")
+	assert.Contains(t, actions, "CREATE_QUERY_LOGS_COLLECTION_GLOBAL")
+	assert.Contains(t, actions, "CREATE_COLLECTION_INTEGRATION")
+	assert.Contains(t, actions, "DELETE_VI")
+}
+
+func TestDefinition_Diff(t *testing.T) {
+	current := role.Definition{Name: "reader", Privileges: []role.Privilege{
+		{Action: "QUERY_DATA_WS", Resource: "commons"},
+		{Action: "LIST_WS_GLOBAL"},
+	}}
+	desired := role.Definition{Name: "reader", Privileges: []role.Privilege{
+		{Action: "QUERY_DATA_WS", Resource: "commons", Cluster: "*ALL*"},
+		{Action: "QUERY_DATA_WS", Resource: "movies"},
+	}}
+
+	added, removed := current.Diff(desired)
+	assert.Equal(t, []role.Privilege{{Action: "QUERY_DATA_WS", Resource: "movies"}}, added)
+	assert.Equal(t, []role.Privilege{{Action: "LIST_WS_GLOBAL"}}, removed)
+}
+
+func TestLoad(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "role.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`name: reader
+description: read only access
+privileges:
+  - action: QUERY_DATA_WS
+    resource: commons
+  - action: LIST_WS_GLOBAL
+`), 0o600))
+
+	d, err := role.Load(file)
+	require.NoError(t, err)
+	assert.Equal(t, "reader", d.Name)
+	assert.Len(t, d.Privileges, 2)
+
+	var buf bytes.Buffer
+	require.NoError(t, role.Write(&buf, d))
+	assert.Contains(t, buf.String(), "resource: commons")
+}
+
+func TestLoad_invalid(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "role.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("name: reader\nprivileges:\n  - action: QUERY_DATA_WS\n"), 0o600))
+
+	_, err := role.Load(file)
+	assert.ErrorContains(t, err, "privilege 1: action QUERY_DATA_WS requires")
+}
+
+func TestGrants(t *testing.T) {
+	roles := []role.Definition{
+		{Name: "reader", Privileges: []role.Privilege{
+			{Action: "QUERY_DATA_WS", Resource: "commons", Cluster: "usw2a1"},
+			{Action: "LIST_WS_GLOBAL"},
+		}},
+		{Name: "ops", Privileges: []role.Privilege{
+			{Action: "ALL_VI_ACTIONS", Resource: "*ALL*", Cluster: "*ALL*"},
+		}},
+	}
+
+	tests := []struct {
+		name     string
+		action   string
+		resource string
+		cluster  string
+		roles    []string
+	}{
+		{"exact", "QUERY_DATA_WS", "commons", "", []string{"reader"}},
+		{"cluster", "QUERY_DATA_WS", "commons", "usw2a1", []string{"reader"}},
+		{"other cluster", "QUERY_DATA_WS", "commons", "use1a1", nil},
+		{"other resource", "QUERY_DATA_WS", "movies", "", nil},
+		{"global", "LIST_WS_GLOBAL", "", "", []string{"reader"}},
+		{"wildcard", "SUSPEND_RESUME_VI", "main", "use1a1", []string{"ops"}},
+		{"not granted", "CREATE_ROLE_GLOBAL", "", "", nil},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			var got []string
+			for _, g := range role.Grants(roles, tst.action, tst.resource, tst.cluster) {
+				got = append(got, g.Role)
+			}
+			assert.Equal(t, tst.roles, got)
+		})
+	}
+}
+
+func TestPrivilege_OnlyIn(t *testing.T) {
+	assert.Equal(t, "usw2a1", role.Privilege{Action: "QUERY_DATA_WS", Resource: "commons", Cluster: "usw2a1"}.OnlyIn())
+	assert.Empty(t, role.Privilege{Action: "QUERY_DATA_WS", Resource: "commons", Cluster: "*ALL*"}.OnlyIn())
+	assert.Empty(t, role.Privilege{Action: "LIST_WS_GLOBAL"}.OnlyIn())
+}
@@ -0,0 +1,271 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Action string
+
+const (
+	Suspend Action = "suspend"
+	Resume  Action = "resume"
+	Resize  Action = "resize"
+)
+
+var Actions = []Action{Suspend, Resume, Resize}
+
+// Schedule is the list of rules for a single virtual instance
+type Schedule struct {
+	// Timezone is the IANA name of the timezone the rules are in, defaults to the local timezone
+	Timezone string `yaml:"timezone,omitempty"`
+	Rules    []Rule `yaml:"rules"`
+}
+
+type Rule struct {
+	Action Action `yaml:"action"`
+	// At is the time of day in 24h format, e.g. 07:30
+	At string `yaml:"at"`
+	// Days are the days of the week the rule applies to, e.g. mon or weekdays, defaults to every day
+	Days []string `yaml:"days,omitempty"`
+	// Size is the new size of the virtual instance, only used by the resize action
+	Size string `yaml:"size,omitempty"`
+}
+
+// Schedules maps virtual instance names to their schedule
+type Schedules map[string]Schedule
+
+// Event is an occurrence of a Rule for a virtual instance
+type Event struct {
+	VI   string
+	Rule Rule
+	At   time.Time
+}
+
+func (e Event) String() string {
+	if e.Rule.Action == Resize {
+		return fmt.Sprintf("%s %s %s to %s", e.At.Format(time.RFC1123), e.Rule.Action, e.VI, e.Rule.Size)
+	}
+
+	return fmt.Sprintf("%s %s %s", e.At.Format(time.RFC1123), e.Rule.Action, e.VI)
+}
+
+var weekdays = map[string][]time.Weekday{
+	"sun":      {time.Sunday},
+	"mon":      {time.Monday},
+	"tue":      {time.Tuesday},
+	"wed":      {time.Wednesday},
+	"thu":      {time.Thursday},
+	"fri":      {time.Friday},
+	"sat":      {time.Saturday},
+	"weekdays": {time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	"weekends": {time.Saturday, time.Sunday},
+	"daily": {time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday,
+		time.Saturday},
+}
+
+// Validate checks that the schedule can be used
+func (s Schedule) Validate() error {
+	if _, err := s.location(); err != nil {
+		return err
+	}
+
+	if len(s.Rules) == 0 {
+		return errors.New("schedule has no rules")
+	}
+
+	for i, r := range s.Rules {
+		if err := r.validate(); err != nil {
+			return fmt.Errorf("rule %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+func (r Rule) validate() error {
+	switch r.Action {
+	case Suspend, Resume:
+		if r.Size != "" {
+			return fmt.Errorf("size can only be used with the %s action", Resize)
+		}
+	case Resize:
+		if r.Size == "" {
+			return fmt.Errorf("the %s action requires a size", Resize)
+		}
+	default:
+		return fmt.Errorf("unknown action '%s', must be one of: %s", r.Action, joinActions())
+	}
+
+	if _, _, err := r.clock(); err != nil {
+		return err
+	}
+
+	if _, err := r.weekdays(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r Rule) clock() (int, int, error) {
+	t, err := time.Parse("15:04", r.At)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time '%s', must be in 24h format HH:MM", r.At)
+	}
+
+	return t.Hour(), t.Minute(), nil
+}
+
+func (r Rule) weekdays() (map[time.Weekday]bool, error) {
+	days := r.Days
+	if len(days) == 0 {
+		days = []string{"daily"}
+	}
+
+	set := make(map[time.Weekday]bool)
+	for _, d := range days {
+		list, found := weekdays[strings.ToLower(d)]
+		if !found {
+			return nil, fmt.Errorf("unknown day '%s'", d)
+		}
+		for _, wd := range list {
+			set[wd] = true
+		}
+	}
+
+	return set, nil
+}
+
+func (s Schedule) location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.Local, nil
+	}
+
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone '%s': %w", s.Timezone, err)
+	}
+
+	return loc, nil
+}
+
+// Between returns the events of the schedule after from, up to and including to, in chronological order
+func (s Schedule) Between(vi string, from, to time.Time) ([]Event, error) {
+	loc, err := s.location()
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	for _, r := range s.Rules {
+		hour, minute, err := r.clock()
+		if err != nil {
+			return nil, err
+		}
+		days, err := r.weekdays()
+		if err != nil {
+			return nil, err
+		}
+
+		start := from.In(loc)
+		for d := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc); !d.After(to); d = d.AddDate(0, 0, 1) {
+			if !days[d.Weekday()] {
+				continue
+			}
+
+			at := time.Date(d.Year(), d.Month(), d.Day(), hour, minute, 0, 0, loc)
+			if at.After(from) && !at.After(to) {
+				events = append(events, Event{VI: vi, Rule: r, At: at})
+			}
+		}
+	}
+
+	sortEvents(events)
+
+	return events, nil
+}
+
+// Between returns the events of all schedules after from, up to and including to, in chronological order
+func (s Schedules) Between(from, to time.Time) ([]Event, error) {
+	var events []Event
+	for vi, schedule := range s {
+		list, err := schedule.Between(vi, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("schedule for %s: %w", vi, err)
+		}
+		events = append(events, list...)
+	}
+
+	sortEvents(events)
+
+	return events, nil
+}
+
+func sortEvents(events []Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].At.Equal(events[j].At) {
+			return events[i].VI < events[j].VI
+		}
+		return events[i].At.Before(events[j].At)
+	})
+}
+
+// Parse reads and validates a single schedule
+func Parse(data []byte) (Schedule, error) {
+	var s Schedule
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return s, err
+	}
+
+	return s, s.Validate()
+}
+
+// Load loads all schedules from file, and if the file doesn't exist, it returns no schedules.
+func Load(file string) (Schedules, error) {
+	s := make(Schedules)
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return s, nil
+		}
+		return s, fmt.Errorf("failed to read schedules: %w", err)
+	}
+
+	if err = yaml.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("failed to parse schedules in %s: %w", file, err)
+	}
+
+	return s, nil
+}
+
+// Store saves all schedules in file
+func Store(file string, s Schedules) error {
+	if err := os.MkdirAll(path.Dir(file), 0700); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, data, 0600)
+}
+
+func joinActions() string {
+	list := make([]string, len(Actions))
+	for i, a := range Actions {
+		list[i] = string(a)
+	}
+
+	return strings.Join(list, ", ")
+}
@@ -0,0 +1,89 @@
+package schedule_test
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/cli/schedule"
+)
+
+const dev = `timezone: UTC
+rules:
+  - action: suspend
+    at: "20:00"
+    days: [weekdays]
+  - action: resume
+    at: "07:00"
+    days: [weekdays]
+  - action: resize
+    at: "12:00"
+    days: [mon]
+    size: LARGE
+`
+
+func TestParse(t *testing.T) {
+	s, err := schedule.Parse([]byte(dev))
+	require.NoError(t, err)
+	assert.Len(t, s.Rules, 3)
+
+	for _, invalid := range []string{
+		"rules: []",
+		"rules: [{action: stop, at: '20:00'}]",
+		"rules: [{action: suspend, at: '8pm'}]",
+		"rules: [{action: suspend, at: '20:00', days: [someday]}]",
+		"rules: [{action: resize, at: '20:00'}]",
+		"timezone: Nowhere/Special\nrules: [{action: suspend, at: '20:00'}]",
+	} {
+		_, err = schedule.Parse([]byte(invalid))
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestSchedule_Between(t *testing.T) {
+	s, err := schedule.Parse([]byte(dev))
+	require.NoError(t, err)
+
+	// Friday 2024-01-05 18:00 until Monday 2024-01-08 18:00
+	from := time.Date(2024, 1, 5, 18, 0, 0, 0, time.UTC)
+	events, err := s.Between("dev", from, from.Add(72*time.Hour))
+	require.NoError(t, err)
+
+	require.Len(t, events, 3)
+	assert.Equal(t, schedule.Suspend, events[0].Rule.Action)
+	assert.Equal(t, time.Date(2024, 1, 5, 20, 0, 0, 0, time.UTC), events[0].At)
+	assert.Equal(t, schedule.Resume, events[1].Rule.Action)
+	assert.Equal(t, time.Date(2024, 1, 8, 7, 0, 0, 0, time.UTC), events[1].At)
+	assert.Equal(t, schedule.Resize, events[2].Rule.Action)
+	assert.Equal(t, "dev", events[2].VI)
+
+	// events exactly at from aren't included, but those at to are
+	events, err = s.Between("dev", events[0].At, events[1].At)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, schedule.Resume, events[0].Rule.Action)
+}
+
+func TestLoadStore(t *testing.T) {
+	file := path.Join(t.TempDir(), "schedules.yaml")
+
+	s, err := schedule.Load(file)
+	require.NoError(t, err)
+	assert.Empty(t, s)
+
+	dev, err := schedule.Parse([]byte(dev))
+	require.NoError(t, err)
+	s["dev"] = dev
+	require.NoError(t, schedule.Store(file, s))
+
+	loaded, err := schedule.Load(file)
+	require.NoError(t, err)
+	assert.Equal(t, s, loaded)
+
+	_, err = os.Stat(file)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxConfirmItems is how many items the confirmation shows, before summarizing the rest
+const maxConfirmItems = 20
+
+// Confirm asks the user to confirm an action, and shows the items it affects
+type Confirm struct {
+	title string
+	items []string
+	done  bool
+	// Confirmed is set when the user answered yes
+	Confirmed bool
+}
+
+func NewConfirm(title string, items []string) *Confirm {
+	return &Confirm{
+		title: title,
+		items: items,
+	}
+}
+
+func (c *Confirm) Init() tea.Cmd {
+	return nil
+}
+
+func (c *Confirm) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "y", "Y":
+			c.Confirmed = true
+			c.done = true
+			return c, tea.Quit
+		case "n", "N", "enter", "esc", "q", "ctrl+c":
+			c.done = true
+			return c, tea.Quit
+		}
+	}
+
+	return c, nil
+}
+
+func (c *Confirm) View() string {
+	var b strings.Builder
+
+	b.WriteString(WarningStyle.Render(c.title) + "\n")
+	for i, item := range c.items {
+		if i == maxConfirmItems {
+			b.WriteString(fmt.Sprintf("  ... and %d more\n", len(c.items)-maxConfirmItems))
+			break
+		}
+		b.WriteString("  " + item + "\n")
+	}
+
+	switch {
+	case !c.done:
+		b.WriteString(helpStyle.Render("y to confirm, n to cancel") + "\n")
+	case c.Confirmed:
+		b.WriteString("confirmed\n")
+	default:
+		b.WriteString("canceled\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,172 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+)
+
+// IngestData is a snapshot of the ingest status of a collection
+type IngestData struct {
+	Collection string `json:"collection"`
+	Status     string `json:"status"`
+	Documents  int64  `json:"documents"`
+	Size       int64  `json:"size"`
+	// FillProgress is between 0 and 1, and is how far the initial load has come
+	FillProgress float64        `json:"fill_progress"`
+	Sources      []IngestSource `json:"sources,omitempty"`
+	Bulk         []IngestBulk   `json:"bulk,omitempty"`
+}
+
+type IngestSource struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	Integration string `json:"integration,omitempty"`
+	State       string `json:"state"`
+	// Message is the last status message, which contains the error when the source failed
+	Message         string    `json:"message,omitempty"`
+	LastProcessedAt time.Time `json:"last_processed_at"`
+	Processed       int64     `json:"processed"`
+	// Lag is the total offset lag of all partitions, only for Kafka sources
+	Lag *int64 `json:"lag,omitempty"`
+}
+
+// IngestBulk is the progress of a bulk ingest
+type IngestBulk struct {
+	Stage               string    `json:"stage"`
+	StartedAt           time.Time `json:"started_at"`
+	DocumentsDownloaded int64     `json:"documents_downloaded"`
+	BytesDownloaded     int64     `json:"bytes_downloaded"`
+	BytesIndexed        int64     `json:"bytes_indexed"`
+	// Throughput is the indexing throughput in bytes per second
+	Throughput float64 `json:"throughput"`
+}
+
+// IngestFetchFn gets a new snapshot
+type IngestFetchFn func(ctx context.Context) (IngestData, error)
+
+// IngestStatus shows the ingest status of a collection, which refreshes periodically
+type IngestStatus struct {
+	poll poller[IngestData]
+
+	data    IngestData
+	updated time.Time
+	err     error
+	width   int
+}
+
+func NewIngestStatus(ctx context.Context, interval time.Duration, fetch IngestFetchFn) *IngestStatus {
+	return &IngestStatus{
+		poll:  poller[IngestData]{ctx: ctx, interval: interval, fetch: fetch},
+		width: maxWidth,
+	}
+}
+
+func (s *IngestStatus) Init() tea.Cmd {
+	return s.poll.next()
+}
+
+func (s *IngestStatus) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc", "q":
+			return s, tea.Quit
+		case "r":
+			return s, s.poll.refresh()
+		}
+
+	case tea.WindowSizeMsg:
+		s.width = msg.Width
+
+	case pollTickMsg:
+		return s, s.poll.next()
+
+	case pollDataMsg[IngestData]:
+		s.err = msg.err
+		if msg.err == nil {
+			s.data = msg.data
+			s.updated = time.Now()
+		}
+	}
+
+	return s, nil
+}
+
+func (s *IngestStatus) View() string {
+	var b strings.Builder
+
+	b.WriteString(RenderIngestStatus(s.data, s.width))
+	b.WriteString(fmt.Sprintf("\nupdated: %s\n", s.updated.Format(time.TimeOnly)))
+	if s.err != nil {
+		b.WriteString(ErrorStyle.Render("refresh failed: "+s.err.Error()) + "\n")
+	}
+	b.WriteString(helpStyle.Render("r to refresh, q to quit"))
+
+	return b.String()
+}
+
+// RenderIngestStatus renders the ingest status to fit the width, or without truncating when the width is 0
+func RenderIngestStatus(data IngestData, width int) string {
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(Purple)
+	header := lipgloss.NewStyle().Bold(true).Foreground(Cyan)
+
+	b.WriteString(fmt.Sprintf("%s %s  status: %s  documents: %s  size: %s\n", Rockset,
+		title.Render(data.Collection), data.Status, humanize.Comma(data.Documents), humanize.Bytes(uint64(data.Size))))
+
+	if data.FillProgress > 0 && data.FillProgress < 1 {
+		bar := progress.New(progress.WithDefaultGradient())
+		bar.Width = maxWidth
+		if width > 0 {
+			bar.Width = min(width-padding*2, maxWidth)
+		}
+		b.WriteString("initial load " + bar.ViewAs(data.FillProgress) + "\n")
+	}
+
+	b.WriteString("\n" + header.Render(fmt.Sprintf("SOURCES (%d)", len(data.Sources))) + "\n")
+	b.WriteString(header.Render(fmt.Sprintf("%-10s  %-20s  %-12s  %14s  %14s  %12s  %s",
+		"TYPE", "INTEGRATION", "STATE", "LAST PROCESSED", "PROCESSED", "LAG", "MESSAGE")) + "\n")
+	for _, src := range data.Sources {
+		last := "never"
+		if !src.LastProcessedAt.IsZero() {
+			last = humanize.Time(src.LastProcessedAt)
+		}
+		lag := "-"
+		if src.Lag != nil {
+			lag = humanize.Comma(*src.Lag)
+		}
+
+		line := fmt.Sprintf("%-10s  %-20s  %-12s  %14s  %14s  %12s  %s", src.Type, truncate(src.Integration, 20),
+			src.State, last, humanize.Comma(src.Processed), lag, oneLine(src.Message))
+		line = truncate(line, width)
+		if strings.Contains(strings.ToUpper(src.State), "ERROR") {
+			line = ErrorStyle.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+
+	if len(data.Bulk) > 0 {
+		b.WriteString("\n" + header.Render(fmt.Sprintf("BULK INGEST (%d)", len(data.Bulk))) + "\n")
+		b.WriteString(header.Render(fmt.Sprintf("%-14s  %14s  %14s  %12s  %12s  %s",
+			"STAGE", "STARTED", "DOCUMENTS", "DOWNLOADED", "INDEXED", "THROUGHPUT")) + "\n")
+		for _, bulk := range data.Bulk {
+			started := "-"
+			if !bulk.StartedAt.IsZero() {
+				started = humanize.Time(bulk.StartedAt)
+			}
+			b.WriteString(truncate(fmt.Sprintf("%-14s  %14s  %14s  %12s  %12s  %s/s", bulk.Stage, started,
+				humanize.Comma(bulk.DocumentsDownloaded), humanize.Bytes(uint64(bulk.BytesDownloaded)),
+				humanize.Bytes(uint64(bulk.BytesIndexed)), humanize.Bytes(uint64(bulk.Throughput))), width) + "\n")
+		}
+	}
+
+	return b.String()
+}
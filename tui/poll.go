@@ -0,0 +1,38 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// poller fetches data for a model which refreshes periodically. Only the tick schedules the next tick,
+// so refreshing manually doesn't start another polling loop.
+type poller[T any] struct {
+	ctx      context.Context
+	interval time.Duration
+	fetch    func(ctx context.Context) (T, error)
+}
+
+type pollTickMsg time.Time
+
+type pollDataMsg[T any] struct {
+	data T
+	err  error
+}
+
+// next fetches the data now, and schedules the next tick. It is used when the model starts and on each tick.
+func (p poller[T]) next() tea.Cmd {
+	return tea.Batch(p.refresh(), tea.Tick(p.interval, func(now time.Time) tea.Msg {
+		return pollTickMsg(now)
+	}))
+}
+
+// refresh fetches the data now, without scheduling a tick
+func (p poller[T]) refresh() tea.Cmd {
+	return func() tea.Msg {
+		data, err := p.fetch(p.ctx)
+		return pollDataMsg[T]{data: data, err: err}
+	}
+}
@@ -0,0 +1,201 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TopData is a snapshot of a virtual instance shown by the Top model
+type TopData struct {
+	Name    string
+	State   string
+	Size    string
+	Queries []TopQuery
+	Mounts  []TopMount
+}
+
+type TopQuery struct {
+	ID      string
+	Status  string
+	User    string
+	Elapsed time.Duration
+	SQL     string
+}
+
+type TopMount struct {
+	Collection string
+	State      string
+	// LastRefresh is the zero time for live mounts
+	LastRefresh time.Time
+}
+
+// TopFetchFn gets a new snapshot
+type TopFetchFn func(ctx context.Context) (TopData, error)
+
+// TopKillFn cancels a query
+type TopKillFn func(ctx context.Context, queryID string) error
+
+// Top is a full screen view of a virtual instance which refreshes periodically,
+// and lets the user kill a running query.
+type Top struct {
+	ctx  context.Context
+	poll poller[TopData]
+	kill TopKillFn
+
+	data    TopData
+	updated time.Time
+	cursor  int
+	// confirm is the ID of the query to kill, which needs to be confirmed
+	confirm string
+	status  string
+	err     error
+	width   int
+}
+
+type topKillMsg struct {
+	id  string
+	err error
+}
+
+func NewTop(ctx context.Context, interval time.Duration, fetch TopFetchFn, kill TopKillFn) *Top {
+	return &Top{
+		ctx:   ctx,
+		poll:  poller[TopData]{ctx: ctx, interval: interval, fetch: fetch},
+		kill:  kill,
+		width: maxWidth,
+	}
+}
+
+func (t *Top) Init() tea.Cmd {
+	return t.poll.next()
+}
+
+func (t *Top) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if t.confirm != "" {
+			id := t.confirm
+			t.confirm = ""
+			if msg.String() == "y" {
+				t.status = fmt.Sprintf("killing query %s...", id)
+				return t, func() tea.Msg {
+					return topKillMsg{id: id, err: t.kill(t.ctx, id)}
+				}
+			}
+			t.status = ""
+			return t, nil
+		}
+
+		switch msg.String() {
+		case "ctrl+c", "esc", "q":
+			return t, tea.Quit
+		case "down", "j":
+			if t.cursor < len(t.data.Queries)-1 {
+				t.cursor++
+			}
+		case "up", "k":
+			if t.cursor > 0 {
+				t.cursor--
+			}
+		case "r":
+			return t, t.poll.refresh()
+		case "x":
+			if t.cursor < len(t.data.Queries) {
+				t.confirm = t.data.Queries[t.cursor].ID
+			}
+		}
+
+	case tea.WindowSizeMsg:
+		t.width = msg.Width
+
+	case pollTickMsg:
+		return t, t.poll.next()
+
+	case pollDataMsg[TopData]:
+		t.err = msg.err
+		if msg.err == nil {
+			t.data = msg.data
+			t.updated = time.Now()
+			if t.cursor >= len(t.data.Queries) {
+				t.cursor = max(len(t.data.Queries)-1, 0)
+			}
+		}
+
+	case topKillMsg:
+		if msg.err != nil {
+			t.status = ErrorStyle.Render(fmt.Sprintf("failed to kill query %s: %v", msg.id, msg.err))
+		} else {
+			t.status = fmt.Sprintf("killed query %s", msg.id)
+		}
+		return t, t.poll.refresh()
+	}
+
+	return t, nil
+}
+
+func (t *Top) View() string {
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(Purple)
+	header := lipgloss.NewStyle().Bold(true).Foreground(Cyan)
+
+	b.WriteString(fmt.Sprintf("%s %s  state: %s  size: %s  updated: %s\n",
+		Rockset, title.Render(t.data.Name), t.data.State, t.data.Size, t.updated.Format(time.TimeOnly)))
+	if t.err != nil {
+		b.WriteString(ErrorStyle.Render("refresh failed: "+t.err.Error()) + "\n")
+	}
+
+	b.WriteString("\n" + header.Render(fmt.Sprintf("QUERIES (%d)", len(t.data.Queries))) + "\n")
+	b.WriteString(header.Render(fmt.Sprintf("   %-36s  %-10s  %10s  %-20s  %s", "ID", "STATUS", "ELAPSED", "USER", "SQL")) + "\n")
+	for i, q := range t.data.Queries {
+		line := fmt.Sprintf("%-36s  %-10s  %10s  %-20s  %s", q.ID, q.Status,
+			q.Elapsed.Round(time.Second), truncate(q.User, 20), oneLine(q.SQL))
+		line = truncate(line, t.width-3)
+		if i == t.cursor {
+			b.WriteString(focusedStyle.Render("-> " + line))
+		} else {
+			b.WriteString("   " + line)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n" + header.Render(fmt.Sprintf("MOUNTS (%d)", len(t.data.Mounts))) + "\n")
+	b.WriteString(header.Render(fmt.Sprintf("   %-40s  %-22s  %s", "COLLECTION", "STATE", "LAST REFRESH")) + "\n")
+	for _, m := range t.data.Mounts {
+		refresh := "live"
+		if !m.LastRefresh.IsZero() {
+			refresh = time.Since(m.LastRefresh).Round(time.Second).String() + " ago"
+		}
+		b.WriteString(truncate(fmt.Sprintf("   %-40s  %-22s  %s", m.Collection, m.State, refresh), t.width) + "\n")
+	}
+
+	b.WriteString("\n")
+	if t.confirm != "" {
+		b.WriteString(WarningStyle.Render(fmt.Sprintf("kill query %s? (y/n)", t.confirm)) + "\n")
+	} else if t.status != "" {
+		b.WriteString(t.status + "\n")
+	}
+	b.WriteString(helpStyle.Render("up/down to select a query, x to kill it, r to refresh, q to quit"))
+
+	return b.String()
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func truncate(s string, width int) string {
+	if width <= 0 || len(s) <= width {
+		return s
+	}
+	if width <= 3 {
+		return s[:width]
+	}
+
+	return s[:width-3] + "..."
+}
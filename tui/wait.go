@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// WaitModel shows a spinner and the state transitions of a resource which is waited for
+type WaitModel struct {
+	spinner  spinner.Model
+	resource string
+	states   []string
+	start    time.Time
+	err      error
+	done     bool
+	// Canceled is set when the user stopped waiting
+	Canceled bool
+}
+
+// WaitStateMsg is sent when the state of the resource changes
+type WaitStateMsg struct{ State string }
+
+func NewWaitModel(resource string) *WaitModel {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = focusedStyle
+
+	return &WaitModel{
+		spinner:  s,
+		resource: resource,
+		start:    time.Now(),
+	}
+}
+
+func (m *WaitModel) Init() tea.Cmd {
+	return m.spinner.Tick
+}
+
+func (m *WaitModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc", "q":
+			m.Canceled = true
+			return m, tea.Quit
+		}
+
+	case WaitStateMsg:
+		m.states = append(m.states, msg.State)
+
+	case DoneMsg:
+		m.done = true
+		return m, tea.Quit
+
+	case ErrMsg:
+		m.err = msg.Err
+		return m, tea.Quit
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m *WaitModel) View() string {
+	transitions := strings.Join(m.states, " → ")
+	elapsed := time.Since(m.start).Round(time.Second)
+
+	switch {
+	case m.done:
+		return fmt.Sprintf("%s %s: %s (%s)\n", RocksetStyle.Render("✓"), m.resource, transitions, elapsed)
+	case m.err != nil:
+		return fmt.Sprintf("%s %s: %s (%s)\n", ErrorStyle.Render("✗"), m.resource, transitions, elapsed)
+	case m.Canceled:
+		return fmt.Sprintf("stopped waiting for %s: %s (%s)\n", m.resource, transitions, elapsed)
+	}
+
+	return fmt.Sprintf("%s waiting for %s: %s (%s)\n%s\n", m.spinner.View(), m.resource, transitions, elapsed,
+		helpStyle.Render("Press q to stop waiting"))
+}
@@ -0,0 +1,148 @@
+// Package users reconciles the users of an organization and their roles with a YAML file
+package users
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/rockset/rockset-go-client/openapi"
+	"gopkg.in/yaml.v3"
+)
+
+// File is the users which should be members of the organization
+type File struct {
+	Users []User `yaml:"users"`
+}
+
+// User is an organization member and its roles
+type User struct {
+	Email string   `yaml:"email"`
+	Roles []string `yaml:"roles"`
+}
+
+// Load reads and validates the users file
+func Load(file string) (File, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return File{}, err
+	}
+
+	var f File
+	if err = yaml.Unmarshal(data, &f); err != nil {
+		return File{}, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+
+	if err = f.Validate(); err != nil {
+		return File{}, fmt.Errorf("invalid users in %s: %w", file, err)
+	}
+
+	return f, nil
+}
+
+// Validate checks that all users have an email address and at least one role, and are only listed once
+func (f File) Validate() error {
+	seen := make(map[string]bool, len(f.Users))
+	for i, u := range f.Users {
+		if u.Email == "" {
+			return fmt.Errorf("user %d is missing an email address", i+1)
+		}
+		if len(u.Roles) == 0 {
+			return fmt.Errorf("user %s must have at least one role", u.Email)
+		}
+
+		email := strings.ToLower(u.Email)
+		if seen[email] {
+			return fmt.Errorf("user %s is listed more than once", u.Email)
+		}
+		seen[email] = true
+	}
+
+	return nil
+}
+
+// Action is what is done to a user
+type Action string
+
+const (
+	Invite Action = "invite"
+	Update Action = "update"
+	Remove Action = "remove"
+)
+
+// Change is a user which is invited, updated or removed
+type Change struct {
+	Action Action
+	Email  string
+	// From is the current roles, and is empty when the user is invited
+	From []string
+	// To is the desired roles, and is empty when the user is removed
+	To []string
+}
+
+func (c Change) String() string {
+	switch c.Action {
+	case Invite:
+		return fmt.Sprintf("+ %s (%s)", c.Email, strings.Join(c.To, ", "))
+	case Update:
+		return fmt.Sprintf("~ %s (%s -> %s)", c.Email, strings.Join(c.From, ", "), strings.Join(c.To, ", "))
+	default:
+		return fmt.Sprintf("- %s (%s)", c.Email, strings.Join(c.From, ", "))
+	}
+}
+
+// ErrSelf is returned by Plan when the current user would be removed
+var ErrSelf = errors.New("can't remove the current user")
+
+// Plan returns the changes needed to make the current users match the file, sorted by email address.
+// Email addresses are compared case-insensitively, and the self user is never removed.
+func Plan(current []openapi.User, f File, self string) ([]Change, error) {
+	existing := make(map[string]openapi.User, len(current))
+	for _, u := range current {
+		existing[strings.ToLower(u.Email)] = u
+	}
+
+	var changes []Change
+	desired := make(map[string]bool, len(f.Users))
+	for _, u := range f.Users {
+		email := strings.ToLower(u.Email)
+		desired[email] = true
+		to := sorted(u.Roles)
+
+		c, found := existing[email]
+		if !found {
+			changes = append(changes, Change{Action: Invite, Email: u.Email, To: to})
+			continue
+		}
+
+		if from := sorted(c.Roles); !slices.Equal(from, to) {
+			changes = append(changes, Change{Action: Update, Email: c.Email, From: from, To: to})
+		}
+	}
+
+	for _, u := range current {
+		if desired[strings.ToLower(u.Email)] {
+			continue
+		}
+		if strings.EqualFold(u.Email, self) {
+			return nil, fmt.Errorf("%w %s, add it to the file", ErrSelf, self)
+		}
+		changes = append(changes, Change{Action: Remove, Email: u.Email, From: sorted(u.Roles)})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return strings.ToLower(changes[i].Email) < strings.ToLower(changes[j].Email)
+	})
+
+	return changes, nil
+}
+
+func sorted(roles []string) []string {
+	s := slices.Clone(roles)
+	sort.Strings(s)
+
+	return slices.Compact(s)
+}
@@ -0,0 +1,77 @@
+package users_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rockset/rockset-go-client/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/cli/users"
+)
+
+func TestPlan(t *testing.T) {
+	current := []openapi.User{
+		{Email: "admin@example.com", Roles: []string{"admin"}},
+		{Email: "Bob@example.com", Roles: []string{"member", "read-only"}},
+		{Email: "carol@example.com", Roles: []string{"member"}},
+		{Email: "dave@example.com", Roles: []string{"member"}},
+	}
+	f := users.File{Users: []users.User{
+		{Email: "admin@example.com", Roles: []string{"admin"}},
+		{Email: "bob@example.com", Roles: []string{"read-only", "member"}},
+		{Email: "carol@example.com", Roles: []string{"admin"}},
+		{Email: "alice@example.com", Roles: []string{"read-only"}},
+	}}
+
+	changes, err := users.Plan(current, f, "admin@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []users.Change{
+		{Action: users.Invite, Email: "alice@example.com", To: []string{"read-only"}},
+		{Action: users.Update, Email: "carol@example.com", From: []string{"member"}, To: []string{"admin"}},
+		{Action: users.Remove, Email: "dave@example.com", From: []string{"member"}},
+	}, changes)
+
+	assert.Equal(t, "+ alice@example.com (read-only)", changes[0].String())
+	assert.Equal(t, "~ carol@example.com (member -> admin)", changes[1].String())
+	assert.Equal(t, "- dave@example.com (member)", changes[2].String())
+}
+
+func TestPlan_self(t *testing.T) {
+	current := []openapi.User{{Email: "admin@example.com", Roles: []string{"admin"}}}
+
+	_, err := users.Plan(current, users.File{}, "Admin@example.com")
+	assert.ErrorIs(t, err, users.ErrSelf)
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name string
+		yaml string
+		err  string
+	}{
+		{"valid", "users:\n  - email: alice@example.com\n    roles: [read-only]\n", ""},
+		{"no email", "users:\n  - roles: [read-only]\n", "user 1 is missing an email address"},
+		{"no roles", "users:\n  - email: alice@example.com\n", "must have at least one role"},
+		{"duplicate", "users:\n  - email: alice@example.com\n    roles: [a]\n  - email: Alice@example.com\n    roles: [b]\n",
+			"listed more than once"},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			file := filepath.Join(dir, tst.name+".yaml")
+			require.NoError(t, os.WriteFile(file, []byte(tst.yaml), 0o600))
+
+			_, err := users.Load(file)
+			if tst.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tst.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package wait
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	rockerr "github.com/rockset/rockset-go-client/errors"
+
+	"github.com/rockset/cli/tui"
+)
+
+// ErrTimeout is returned when the resource didn't reach the desired state before the timeout
+var ErrTimeout = errors.New("timed out")
+
+// ExitCodeTimeout is the exit code used when waiting timed out, the same as used by timeout(1)
+const ExitCodeTimeout = 124
+
+// DefaultInterval is how often the state is polled unless the Waiter specifies otherwise
+const DefaultInterval = 2 * time.Second
+
+// StateNotFound is the state of a resource which doesn't exist, so it can be used to wait until it is gone
+const StateNotFound = "NOT_FOUND"
+
+// StateFn returns the current state of the resource
+type StateFn func(ctx context.Context) (string, error)
+
+// Target is the resource to wait for
+type Target struct {
+	// Resource describes the resource in the output, e.g. "virtual instance main"
+	Resource string
+	// Ready are the states which ends the wait
+	Ready []string
+	// Failed are the states which the resource can't get out of, which ends the wait with an error
+	Failed []string
+	State  StateFn
+}
+
+// Waiter polls the state of a Target until it is ready, showing the state transitions either
+// using a spinner when Interactive, or a line per transition otherwise.
+type Waiter struct {
+	Out         io.Writer
+	Interactive bool
+	// Interval is how often the state is polled, defaults to DefaultInterval
+	Interval time.Duration
+	// Timeout is how long to wait, 0 means wait forever
+	Timeout time.Duration
+}
+
+// Until waits until the target is in one of its ready states
+func (w Waiter) Until(ctx context.Context, t Target) error {
+	if w.Interval == 0 {
+		w.Interval = DefaultInterval
+	}
+
+	if w.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.Timeout)
+		defer cancel()
+	}
+
+	var err error
+	if w.Interactive {
+		err = w.interactive(ctx, t)
+	} else {
+		err = w.poll(ctx, t, func(state string, elapsed time.Duration) {
+			_, _ = fmt.Fprintf(w.Out, "%s is %s (%s)\n", t.Resource, state, elapsed.Round(time.Second))
+		})
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w after %s waiting for %s to be %s", ErrTimeout, w.Timeout, t.Resource,
+			strings.Join(t.Ready, " or "))
+	}
+
+	return err
+}
+
+// Using waits using fn, e.g. one of the go client's waiters, which is given a context which ends after the timeout
+func (w Waiter) Using(ctx context.Context, what string, fn func(ctx context.Context) error) error {
+	if w.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.Timeout)
+		defer cancel()
+	}
+
+	_, _ = fmt.Fprintf(w.Out, "waiting until %s...\n", what)
+	err := fn(ctx)
+	if w.Timeout > 0 && errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w after %s waiting until %s", ErrTimeout, w.Timeout, what)
+	}
+	if err != nil {
+		return fmt.Errorf("failed waiting until %s: %w", what, err)
+	}
+
+	return nil
+}
+
+func (w Waiter) interactive(ctx context.Context, t Target) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	model := tui.NewWaitModel(t.Resource)
+	p := tea.NewProgram(model, tea.WithOutput(w.Out))
+
+	result := make(chan error, 1)
+	go func() {
+		err := w.poll(ctx, t, func(state string, _ time.Duration) {
+			p.Send(tui.WaitStateMsg{State: state})
+		})
+		if err != nil {
+			p.Send(tui.ErrMsg{Err: err})
+		} else {
+			p.Send(tui.DoneMsg{})
+		}
+		result <- err
+	}()
+
+	if _, err := p.Run(); err != nil {
+		return err
+	}
+
+	if model.Canceled {
+		return context.Canceled
+	}
+
+	return <-result
+}
+
+// poll calls fn every time the state changes, until it reaches a ready or failed state
+func (w Waiter) poll(ctx context.Context, t Target, fn func(state string, elapsed time.Duration)) error {
+	start := time.Now()
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	var last string
+	for {
+		state, err := t.State(ctx)
+		if err != nil {
+			var re rockerr.Error
+			// a newly created resource might not be visible yet
+			if !errors.As(err, &re) || !re.IsNotFoundError() {
+				return err
+			}
+			state = StateNotFound
+		}
+
+		if state != last {
+			fn(state, time.Since(start))
+			last = state
+		}
+
+		if slices.Contains(t.Ready, state) {
+			return nil
+		}
+		if slices.Contains(t.Failed, state) {
+			return fmt.Errorf("%s is %s", t.Resource, state)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
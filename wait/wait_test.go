@@ -0,0 +1,96 @@
+package wait_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/cli/wait"
+)
+
+func states(list ...string) wait.StateFn {
+	var i int
+	return func(ctx context.Context) (string, error) {
+		s := list[min(i, len(list)-1)]
+		i++
+		return s, nil
+	}
+}
+
+func TestWaiter_Until(t *testing.T) {
+	var buf bytes.Buffer
+	w := wait.Waiter{Out: &buf, Interval: time.Millisecond}
+
+	err := w.Until(context.Background(), wait.Target{
+		Resource: "virtual instance vi",
+		Ready:    []string{"ACTIVE"},
+		State:    states("INITIALIZING", "INITIALIZING", "PROVISIONING_RESOURCES", "ACTIVE"),
+	})
+	require.NoError(t, err)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 3)
+	assert.Contains(t, string(lines[0]), "virtual instance vi is INITIALIZING")
+	assert.Contains(t, string(lines[1]), "virtual instance vi is PROVISIONING_RESOURCES")
+	assert.Contains(t, string(lines[2]), "virtual instance vi is ACTIVE")
+}
+
+func TestWaiter_UntilFailed(t *testing.T) {
+	var buf bytes.Buffer
+	w := wait.Waiter{Out: &buf, Interval: time.Millisecond}
+
+	err := w.Until(context.Background(), wait.Target{
+		Resource: "query lambda ql",
+		Ready:    []string{"ACTIVE"},
+		Failed:   []string{"INVALID_SQL"},
+		State:    states("CREATING", "INVALID_SQL"),
+	})
+	assert.EqualError(t, err, "query lambda ql is INVALID_SQL")
+}
+
+func TestWaiter_UntilTimeout(t *testing.T) {
+	var buf bytes.Buffer
+	w := wait.Waiter{Out: &buf, Interval: time.Millisecond, Timeout: 20 * time.Millisecond}
+
+	err := w.Until(context.Background(), wait.Target{
+		Resource: "collection ws.coll",
+		Ready:    []string{"READY"},
+		State:    states("INITIALIZED"),
+	})
+	assert.ErrorIs(t, err, wait.ErrTimeout)
+	assert.ErrorContains(t, err, "collection ws.coll to be READY")
+}
+
+func TestWaiter_Using(t *testing.T) {
+	var buf bytes.Buffer
+	w := wait.Waiter{Out: &buf}
+
+	err := w.Using(context.Background(), "view ws.v is deleted", func(ctx context.Context) error {
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "waiting until view ws.v is deleted...\n", buf.String())
+
+	err = w.Using(context.Background(), "view ws.v is deleted", func(ctx context.Context) error {
+		return errors.New("forbidden")
+	})
+	assert.EqualError(t, err, "failed waiting until view ws.v is deleted: forbidden")
+	assert.NotErrorIs(t, err, wait.ErrTimeout)
+}
+
+func TestWaiter_UsingTimeout(t *testing.T) {
+	var buf bytes.Buffer
+	w := wait.Waiter{Out: &buf, Timeout: 20 * time.Millisecond}
+
+	err := w.Using(context.Background(), "view ws.v is deleted", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.ErrorIs(t, err, wait.ErrTimeout)
+	assert.ErrorContains(t, err, "waiting until view ws.v is deleted")
+}